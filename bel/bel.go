@@ -0,0 +1,43 @@
+package bel
+
+import (
+	_ "embed"
+
+	tr "github.com/stts-se/translit"
+)
+
+// https://en.wikipedia.org/wiki/Romanization_of_Belarusian
+// Official national system (2023)
+
+//go:embed tables/national.tsv
+var maptableData string
+
+var maptable = tr.MustParseTable("national.tsv", maptableData)
+
+var nationalScheme = tr.Scheme{
+	ID:          "be-Latn-x-national",
+	Source:      "be-Cyrl",
+	Target:      "be-Latn",
+	Description: "Belarusian to Latin script, official national system (2023)",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Romanization_of_Belarusian",
+	},
+}
+
+var translit = tr.NewTableTranslit(tr.TableConfig{
+	Scheme:         nationalScheme,
+	Table:          maptable,
+	UpcaseDigraphs: true,
+})
+
+// Translit converts Belarusian to Latin script
+type Translit = tr.TableTranslit
+
+// NewTranslit returns a transliterator from Belarusian to Latin script
+func NewTranslit() Translit {
+	return translit
+}
+
+func init() {
+	tr.Register(nationalScheme, func() tr.Transliterator { return NewTranslit() })
+}
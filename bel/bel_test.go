@@ -0,0 +1,31 @@
+package bel
+
+import (
+	"testing"
+)
+
+var fsExpGot = "Expected: %v got: %v"
+
+func TestTransliterate(t *testing.T) {
+	tlit := NewTranslit()
+	for input, expect := range map[string]string{
+		"Беларусь Мінск Гомель":    "Bielaruś Minsk Homieĺ",
+		"Магілёў Віцебск Брэст":    "Mahilioŭ Viciebsk Brest",
+		"Ельск Дзяржынск Заслаўе":  "Jeĺsk Dziaržynsk Zaslaŭje",
+		"Лёзна Шчучын Чачэрск":     "Liozna Ščučyn Čačersk",
+		"Любань Хойнікі Ганцавічы": "Liubań Chojniki Hancavičy",
+		"Юхнаўка Рагачоў Смаргонь": "Juchnaŭka Rahačoŭ Smarhoń",
+		"сям'я Вілейка Жодзіна":    "siamja Viliejka Žodzina",
+		// all caps
+		"ЮХНАЎКА ДЗЯРЖЫНСК ЛЁЗНА": "JUCHNAŬKA DZIARŽYNSK LIOZNA",
+	} {
+		res, err := tlit.Transliterate(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+	}
+}
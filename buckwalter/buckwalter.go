@@ -1,11 +1,15 @@
 package buckwalter
 
 import (
+	_ "embed"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
+	"github.com/stts-se/translit"
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
 	"golang.org/x/text/unicode/runenames"
@@ -34,81 +38,32 @@ func (m maptable) name() string {
 }
 
 var defaultChar = '?'
-var charset = []ch{
-	{'ا', 'A'}, // bare alif
-	{'ب', 'b'},
-	{'ت', 't'},
-	{'ث', 'v'},
-	{'ج', 'j'},
-	{'ح', 'H'},
-	{'خ', 'x'},
-	{'د', 'd'}, // dal \u062F
-	{'ذ', '*'},
-	{'ر', 'r'},
-	{'ز', 'z'},
-	{'س', 's'},
-	{'ش', '$'},
-	{'ص', 'S'},
-	{'ض', 'D'},
-	{'ط', 'T'},
-	{'ظ', 'Z'},
-	{'ع', 'E'},
-	{'غ', 'g'},
-	{'ف', 'f'},
-	{'ق', 'q'},
-	{'ك', 'k'},
-	{'ل', 'l'},
-	{'م', 'm'},
-	{'ن', 'n'},
-	{'ه', 'h'},
-	{'و', 'w'},
-	{'ي', 'y'},
-	{'ة', 'p'}, //teh marbuta
-
-	{'\u064E', 'a'}, // fatha
-	{'\u064f', 'u'}, // damma
-	{'\u0650', 'i'}, // kasra
-	{'\u064B', 'F'}, // fathatayn
-	{'\u064C', 'N'}, // dammatayn
-	{'\u064D', 'K'}, // kasratayn
-	{'\u0651', '~'}, // shadda
-	{'\u0652', 'o'}, // sukun
-
-	{'\u0621', '\''}, // lone hamza
-	{'\u0623', '>'},  // hamza on alif
-	{'\u0625', '<'},  // hamza below alif
-	{'\u0624', '&'},  // hamza on wa
-	{'\u0626', '}'},  // hamza on ya
-
-	{'\u0622', '|'}, // madda on alif
-	{'\u0671', '{'}, // alif al-wasla
-	{'\u0670', '`'}, // dagger alif
-	{'\u0649', 'Y'}, // alif maqsura
-
-	// Arabic-indic digits
-	{'\u0660', '0'},
-	{'\u0661', '1'},
-	{'\u0662', '2'},
-	{'\u0663', '3'},
-	{'\u0664', '4'},
-	{'\u0665', '5'},
-	{'\u0666', '6'},
-	{'\u0667', '7'},
-	{'\u0668', '8'},
-	{'\u0669', '9'},
-
-	// punctuation
-	{'\u060C', ','},
-	{'\u061B', ';'},
-	{'\u061F', '?'},
-
-	// http://www.qamus.org/transliteration.htm
-	{'\u067e', 'P'}, // peh
-	{'\u0686', 'J'}, // tcheh
-	{'\u06a4', 'V'}, // veh
-	{'\u06af', 'G'}, // gaf
-	//ch{'\u0640', '_'}, // tatweel
 
+//go:embed tables/buckwalter.tsv
+var charsetData string
+
+var charsetTable = translit.MustParseTable("buckwalter.tsv", charsetData)
+var charset = mustBuildCharset(charsetTable)
+
+// buildCharset creates a list of character mappings from a table, where each rule must map a single rune to a single rune
+func buildCharset(t translit.Table) ([]ch, error) {
+	res := []ch{}
+	for _, r := range t.Rules {
+		ar, bw := []rune(r.From), []rune(r.To)
+		if len(ar) != 1 || len(bw) != 1 {
+			return res, fmt.Errorf("%s: invalid rule '%s' -> '%s' (expected single characters)", t.Name, r.From, r.To)
+		}
+		res = append(res, ch{ar[0], bw[0]})
+	}
+	return res, nil
+}
+
+func mustBuildCharset(t translit.Table) []ch {
+	res, err := buildCharset(t)
+	if err != nil {
+		panic(err)
+	}
+	return res
 }
 
 var commonChars = map[rune]bool{
@@ -132,7 +87,7 @@ func isCommonChar(sym rune) bool {
 	return false
 }
 
-func makeAr2bwMap() maptable {
+func makeAr2bwMap(charset []ch) maptable {
 	m := map[rune]rune{}
 	for _, ch := range charset {
 		m[ch.ar] = ch.bw
@@ -140,7 +95,7 @@ func makeAr2bwMap() maptable {
 	return maptable{"ar", "bw", m}
 }
 
-func makeBw2ArMap() maptable {
+func makeBw2ArMap(charset []ch) maptable {
 	m := map[rune]rune{}
 	for _, ch := range charset {
 		m[ch.bw] = ch.ar
@@ -148,8 +103,8 @@ func makeBw2ArMap() maptable {
 	return maptable{"bw", "ar", m}
 }
 
-var ar2bwMap = makeAr2bwMap()
-var bw2arMap = makeBw2ArMap()
+var ar2bwMap = makeAr2bwMap(charset)
+var bw2arMap = makeBw2ArMap(charset)
 
 var bwDenormRe = regexp.MustCompile("([aiuoFKN])(~)")
 var bwDenormReTo = "$2$1"
@@ -162,16 +117,22 @@ func arPostNorm(s string) string {
 func bwPostNorm(s string) string {
 	return bwDenormRe.ReplaceAllString(s, bwDenormReTo)
 }
-func arPreNorm(s string) string {
-	var res = s
-	res = strings.Replace(res, "\uFEAA", "\u062F", -1) // DAL FINAL FORM => DAL
-	res = strings.Replace(res, "\u06BE", "\u0647", -1) // HEH DOACHASHMEE => HEH
-	res = strings.Replace(res, "\u200F", "", -1)       // RTL MARK
-	return res
+
+// arPreNorm returns the normalised rune, and false if the rune should be removed
+func arPreNorm(r rune) (rune, bool) {
+	switch r {
+	case '\uFEAA': // DAL FINAL FORM => DAL
+		return '\u062F', true
+	case '\u06BE': // HEH DOACHASHMEE => HEH
+		return '\u0647', true
+	case '\u200F': // RTL MARK
+		return r, false
+	}
+	return r, true
 }
 
-func bwPreNorm(s string) string {
-	return s
+func bwPreNorm(r rune) (rune, bool) {
+	return r, true
 }
 
 func postNormalise(outputName, s string) string {
@@ -181,73 +142,229 @@ func postNormalise(outputName, s string) string {
 	return arPostNorm(s)
 }
 
-func preNormalise(outputName, s string) string {
+func preNormalise(outputName string, r rune) (rune, bool) {
 	if outputName == "bw" {
-		return bwPreNorm(s)
+		return bwPreNorm(r)
 	} else {
-		return arPreNorm(s)
+		return arPreNorm(r)
 	}
 }
 
-func reverseTest(mapTo string, input string, mapped string) error {
-	remaptable := maptable{}
-	if mapTo == ar2bwMap.to {
-		remaptable = ar2bwMap
-	} else if mapTo == bw2arMap.to {
-		remaptable = bw2arMap
-	}
-	remapped, err := convert(remaptable, mapped, false)
+func reverseTest(remaptable maptable, input string, mapped string) error {
+	remapped, _, err := convert(remaptable, maptable{}, mapped, false, translit.UnknownPolicy{})
 	if err != nil {
 		return err
 	}
 	if remapped != input {
-		return fmt.Errorf("reverse test failed: input '%s', mapped '%s', remapped '%s'", input, mapped, remapped)
+		err := translit.NewRoundTripError(input, mapped, remapped)
+		err.Scheme = bwScheme.ID
+		return err
 	}
 	return nil
 }
 
-func convert(maptable maptable, input string, doReverseTest bool) (string, error) {
+// convert converts the input string. Unknown symbols are replaced by defaultChar and reported as an error, unless the policy says otherwise. The reverse test is skipped if unknown symbols are handled by the policy.
+func convert(maptable maptable, remaptable maptable, input string, doReverseTest bool, policy translit.UnknownPolicy) (string, []translit.Segment, error) {
 	//fmt.Fprintf(os.Stderr, "convert from %s | input: %s\n", mapName, input)
-	input = preNormalise(maptable.from, input)
+	normed := []rune{}
 	res := []rune{}
-	errs := []string{}
-	for _, sym := range input {
+	segs := []translit.Segment{}
+	clusterStarts := []bool{}
+	outLen := 0
+	unmapped := []int{}
+	handled := false
+	origInput := input
+	for i, ri := 0, 0; i < len(input); ri++ {
+		sym, size := utf8.DecodeRuneInString(input[i:])
+		seg := translit.Segment{InputStart: i, InputEnd: i + size, OutputStart: outLen, OutputEnd: outLen}
+		i += size
+		sym, keep := preNormalise(maptable.from, sym)
+		if !keep {
+			segs = append(segs, seg)
+			clusterStarts = append(clusterStarts, false)
+			continue
+		}
+		normed = append(normed, sym)
 		mapped, exists := maptable.table[rune(sym)]
-		if !exists {
+		out := string(mapped)
+		if exists {
+			seg.Rule = translit.Rule{From: string(sym), To: out}
+		} else {
 			if ok := isCommonChar(sym); ok {
-				mapped = sym
+				mapped, out = sym, string(sym)
+			} else if replacement, ok := policy.Handle(sym); ok {
+				handled = true
+				mapped, out = sym, replacement
 			} else {
-				mapped = defaultChar
-				errs = append(errs, fmt.Sprintf("no mapping for %s symbol '%s'", maptable.name(), string(sym)))
+				mapped, out = defaultChar, string(defaultChar)
+				unmapped = append(unmapped, ri)
 			}
 		}
-		res = append(res, mapped)
+		res = append(res, []rune(out)...)
+		outLen += len(out)
+		seg.OutputEnd = outLen
+		segs = append(segs, seg)
+		arabic := sym
+		if maptable.to == "ar" {
+			arabic = mapped
+		}
+		clusterStarts = append(clusterStarts, !unicode.Is(unicode.Mn, arabic))
 		//fmt.Fprintf(os.Stderr, "convert | '%s' -> '%s'\n", string(sym), string(mapped))
 	}
+	input = string(normed)
 	mapped := string(res)
-	mapped = postNormalise(maptable.to, mapped)
+	normedMapped := postNormalise(maptable.to, mapped)
+	if normedMapped != mapped {
+		segs = realign(segs, clusterStarts, maptable.to, mapped, normedMapped)
+	}
+	mapped = normedMapped
 
-	if len(errs) > 0 {
-		err := strings.Join(errs, "; ")
-		return mapped, fmt.Errorf("%s", err)
+	if len(unmapped) > 0 {
+		err := translit.NewError(origInput, unmapped)
+		err.Scheme = bwScheme.ID
+		if policy.Action == translit.UnknownFail {
+			return "", nil, err
+		}
+		return mapped, segs, err
 	}
-	if doReverseTest {
-		err := reverseTest(maptable.from, input, mapped)
+	if doReverseTest && !handled {
+		err := reverseTest(remaptable, input, mapped)
+		var e *translit.RoundTripError
+		if errors.As(err, &e) && input == origInput {
+			e.Segment = translit.Result{Input: input, Output: mapped, Segments: segs}.SegmentAt(e.Offset)
+		}
 		if err != nil {
-			return mapped, err
+			return mapped, segs, err
 		}
 	}
-	return mapped, nil
+	return mapped, segs, nil
+}
+
+// realign adjusts the segments to the post-normalised output. Post-normalisation is applied to each Arabic combining sequence (a base character followed by combining marks) separately, and the segments of a sequence that is changed by the normalisation are merged into one.
+func realign(segs []translit.Segment, clusterStarts []bool, outputName, mapped, normedMapped string) []translit.Segment {
+	res := []translit.Segment{}
+	var out strings.Builder
+	for i := 0; i < len(segs); {
+		k := i + 1
+		for k < len(segs) && !clusterStarts[k] {
+			k++
+		}
+		chunk := mapped[segs[i].OutputStart:segs[k-1].OutputEnd]
+		normed := postNormalise(outputName, chunk)
+		outStart := out.Len()
+		out.WriteString(normed)
+		if normed == chunk {
+			for _, seg := range segs[i:k] {
+				seg.OutputStart += outStart - segs[i].OutputStart
+				seg.OutputEnd += outStart - segs[i].OutputStart
+				res = append(res, seg)
+			}
+		} else {
+			res = append(res, translit.Segment{InputStart: segs[i].InputStart, InputEnd: segs[k-1].InputEnd, OutputStart: outStart, OutputEnd: out.Len()})
+		}
+		i = k
+	}
+	if out.String() != normedMapped && len(segs) > 0 {
+		// normalisation across sequences, fall back to a single segment
+		return []translit.Segment{{InputStart: 0, InputEnd: segs[len(segs)-1].InputEnd, OutputStart: 0, OutputEnd: len(normedMapped)}}
+	}
+	return res
 }
 
 // Bw2Ar converts an input Buckwalter string into Arabic alphabet. An error is returned if there are unknown symbols in the input string. The resulting string always contains the full converted string (but with '?' for unknown chars). The Arabic output is NFC normalised (cons + vowel + cons length).
 func Bw2Ar(s string) (string, error) {
-	return convert(bw2arMap, s, true)
+	res, _, err := convert(bw2arMap, ar2bwMap, s, true, translit.UnknownPolicy{})
+	return res, err
 }
 
 // Ar2Bw converts an input Arabic string into Buckwalter. An error is returned if there are unknown symbols in the input string. The resulting string always contains the full converted string (but with '?' for unknown chars). The output is in Buckwalter order (cons + cons length + vowel) -- i.e., not matching Arabic script NFC normalisation.
 func Ar2Bw(s string) (string, error) {
-	return convert(ar2bwMap, s, true)
+	res, _, err := convert(ar2bwMap, bw2arMap, s, true, translit.UnknownPolicy{})
+	return res, err
+}
+
+// Translit
+type Translit struct {
+	table        translit.Table // user modified mapping table (see Extend)
+	ar2bw, bw2ar *maptable
+	unknown      translit.UnknownPolicy
+}
+
+func NewTranslit() Translit {
+	return Translit{}
+}
+
+var _ translit.Reverser = Translit{}
+var _ translit.Extensible = Translit{}
+var _ translit.UnknownHandler = Translit{}
+
+var bwScheme = translit.Scheme{
+	ID:          "ar-Latn-x-buckwalter",
+	Source:      "ar-Arab",
+	Target:      "ar-Latn",
+	Description: "Arabic to Buckwalter transliteration",
+	References: []string{
+		"http://www.qamus.org/transliteration.htm",
+		"https://en.wikipedia.org/wiki/Buckwalter_transliteration",
+	},
+}
+
+func init() {
+	translit.Register(bwScheme, func() translit.Transliterator { return NewTranslit() })
+}
+
+// Scheme returns the scheme metadata
+func (t Translit) Scheme() translit.Scheme {
+	return bwScheme
+}
+
+// Transliterate converts the NFC normalised input string from Arabic into Buckwalter (see Ar2Bw)
+func (t Translit) Transliterate(s string) (translit.Result, error) {
+	s = translit.NFC(s)
+	ar2bw, bw2ar := t.maps()
+	res, segs, err := convert(ar2bw, bw2ar, s, true, t.unknown)
+	return translit.Result{Input: s, Output: res, Segments: segs}, err
+}
+
+// Reverse converts the NFC normalised input string from Buckwalter into Arabic (see Bw2Ar)
+func (t Translit) Reverse(s string) (translit.Result, error) {
+	s = translit.NFC(s)
+	ar2bw, bw2ar := t.maps()
+	res, segs, err := convert(bw2ar, ar2bw, s, true, t.unknown)
+	return translit.Result{Input: s, Output: res, Segments: segs}, err
+}
+
+// WithUnknownPolicy returns a copy of the transliterator, using the policy for unknown input symbols. By default, unknown symbols are replaced by '?', and reported as an error.
+func (t Translit) WithUnknownPolicy(p translit.UnknownPolicy) translit.Transliterator {
+	t.unknown = p
+	return t
+}
+
+func (t Translit) maps() (maptable, maptable) {
+	if t.ar2bw != nil {
+		return *t.ar2bw, *t.bw2ar
+	}
+	return ar2bwMap, bw2arMap
+}
+
+// Table returns the mapping table (Arabic to Buckwalter)
+func (t Translit) Table() translit.Table {
+	if t.ar2bw != nil {
+		return t.table
+	}
+	return charsetTable
+}
+
+// Extend returns a copy of the transliterator, with the rules of the input table added to the mapping table. Each rule must map a single Arabic character to a single Buckwalter character.
+func (t Translit) Extend(table translit.Table) (translit.Transliterator, error) {
+	merged := t.Table().Merge(table)
+	charset, err := buildCharset(merged)
+	if err != nil {
+		return t, err
+	}
+	ar2bw, bw2ar := makeAr2bwMap(charset), makeBw2ArMap(charset)
+	t.table, t.ar2bw, t.bw2ar = merged, &ar2bw, &bw2ar
+	return t, nil
 }
 
 func blockFor(r rune) string {
@@ -1,7 +1,12 @@
 package buckwalter
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/stts-se/translit"
 )
 
 var errFmt = "expected '%s', got '%s'"
@@ -207,3 +212,37 @@ func TestUpdates20180918(t *testing.T) {
 		t.Errorf(errFmt, exp2, got2)
 	}
 }
+
+func TestSegments(t *testing.T) {
+	inp := "مُحَمَّد" // muHam~ad, with Arabic shadda + fatha order
+	res, err := NewTranslit().Transliterate(inp)
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if res.Output != "muHam~ad" {
+		t.Errorf(errFmt, "muHam~ad", res.Output)
+	}
+	exp := []string{"m", "u", "H", "a", "m~a", "d"}
+	got := []string{}
+	for _, seg := range res.Segments {
+		got = append(got, res.Output[seg.OutputStart:seg.OutputEnd])
+	}
+	if strings.Join(got, " ") != strings.Join(exp, " ") {
+		t.Errorf(errFmt, exp, got)
+	}
+	if last := res.Segments[len(res.Segments)-1]; res.Input[last.InputStart:last.InputEnd] != "د" || last.Rule.To != "d" {
+		t.Errorf(errFmt, "د -> d", res.Input[last.InputStart:last.InputEnd]+" -> "+last.Rule.To)
+	}
+}
+
+func TestError(t *testing.T) {
+	_, err := NewTranslit().Reverse("Allh#%")
+	var e *translit.Error
+	if !errors.As(err, &e) {
+		t.Errorf(errFmt, "*translit.Error", err)
+		return
+	}
+	if e.Scheme != "ar-Latn-x-buckwalter" || e.Offset != 4 || len(e.Unmapped) != 2 {
+		t.Errorf(errFmt, "ar-Latn-x-buckwalter 4 [4 5]", fmt.Sprintf("%s %d %v", e.Scheme, e.Offset, e.Unmapped))
+	}
+}
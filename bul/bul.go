@@ -0,0 +1,44 @@
+package bul
+
+import (
+	_ "embed"
+
+	tr "github.com/stts-se/translit"
+)
+
+// https://en.wikipedia.org/wiki/Romanization_of_Bulgarian
+// Streamlined System, official since 2009
+
+//go:embed tables/streamlined.tsv
+var maptableData string
+
+var maptable = tr.MustParseTable("streamlined.tsv", maptableData)
+
+var streamlinedScheme = tr.Scheme{
+	ID:          "bg-Latn-x-streamlined",
+	Source:      "bg-Cyrl",
+	Target:      "bg-Latn",
+	Description: "Bulgarian to Latin script, Streamlined System (2009)",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Romanization_of_Bulgarian",
+	},
+}
+
+var translit = tr.NewTableTranslit(tr.TableConfig{
+	Scheme:         streamlinedScheme,
+	Table:          maptable,
+	CommonChars:    "'’",
+	UpcaseDigraphs: true,
+})
+
+// Translit converts Bulgarian to Latin script
+type Translit = tr.TableTranslit
+
+// NewTranslit returns a transliterator from Bulgarian to Latin script
+func NewTranslit() Translit {
+	return translit
+}
+
+func init() {
+	tr.Register(streamlinedScheme, func() tr.Transliterator { return NewTranslit() })
+}
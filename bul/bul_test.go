@@ -0,0 +1,33 @@
+package bul
+
+import (
+	"testing"
+)
+
+var fsExpGot = "Expected: %v got: %v"
+
+// Examples from the Transliteration Act
+func TestTransliterate(t *testing.T) {
+	tlit := NewTranslit()
+	for input, expect := range map[string]string{
+		"Шишков Съединение Щастливец": "Shishkov Saedinenie Shtastlivets",
+		"Юрий Пловдив Стара Загора":   "Yuriy Plovdiv Stara Zagora",
+		"Горна Оряховица Кърджали":    "Gorna Oryahovitsa Kardzhali",
+		"София България":              "Sofia Bulgaria",
+		"Ямбол Хасково Царево":        "Yambol Haskovo Tsarevo",
+		"Шофьор Благоевград":          "Shofyor Blagoevgrad",
+		"Рияд ИЯ":                     "Riyad IA",
+		// all caps
+		"ЩАСТЛИВЕЦ ЖИТНИЦА КЪРДЖАЛИ": "SHTASTLIVETS ZHITNITSA KARDZHALI",
+		"ЮРИЙ ЦАРЕВО Шишков":         "YURIY TSAREVO Shishkov",
+	} {
+		res, err := tlit.Transliterate(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+	}
+}
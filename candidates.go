@@ -0,0 +1,180 @@
+package translit
+
+import (
+	"sort"
+)
+
+// Candidate is one of several possible conversions of an ambiguous input string
+type Candidate struct {
+	Output   string
+	Cost     int       // Lower is better: the sum of the alternative rank of each rule used, plus one for each rule used where a longer rule could have been used
+	Segments []Segment // Alignment of input and output (see Result)
+}
+
+// CandidateReverser is implemented by reversible transliterators that can return several candidates for ambiguous input in the target script, most likely first
+type CandidateReverser interface {
+	Reverser
+	// ReverseCandidates converts the input string from the target script into the source script, and returns at most max candidates, most likely first
+	ReverseCandidates(input string, max int) ([]Candidate, error)
+}
+
+// DefaultMaxCandidates is the default maximum number of candidates returned
+const DefaultMaxCandidates = 10
+
+// CandidateConverter converts strings into ranked candidates. Rules with the same source string are alternatives, in order of preference, with contextual rules (if their context matches) before rules without context. Unlike Converter, shorter rules are also tried where longer rules match, but with a higher cost.
+type CandidateConverter struct {
+	rules        map[string][]Rule
+	maxLen       int
+	isCommonChar func(r rune) bool
+	unknown      UnknownPolicy
+}
+
+// NewCandidateConverter creates a candidate converter for the mapping rules. The isCommonChar function may be nil.
+func NewCandidateConverter(rules []Rule, isCommonChar func(r rune) bool) CandidateConverter {
+	res := CandidateConverter{rules: map[string][]Rule{}, isCommonChar: isCommonChar}
+	for _, r := range rules {
+		if r.From == "" {
+			continue
+		}
+		res.rules[r.From] = append(res.rules[r.From], r)
+		if n := len([]rune(r.From)); n > res.maxLen {
+			res.maxLen = n
+		}
+	}
+	if res.isCommonChar == nil {
+		res.isCommonChar = func(r rune) bool { return false }
+	}
+	return res
+}
+
+// WithUnknownPolicy returns a copy of the converter, using the policy for input characters that aren't covered by a rule or accepted as a common char
+func (c CandidateConverter) WithUnknownPolicy(p UnknownPolicy) CandidateConverter {
+	c.unknown = p
+	return c
+}
+
+// alternatives returns the rules for the input runes from start to end, with matching contextual rules first, and duplicate targets removed
+func (c CandidateConverter) alternatives(rs []rune, start, end int) []Rule {
+	rules := c.rules[string(rs[start:end])]
+	res := []Rule{}
+	seen := map[string]bool{}
+	for _, contextual := range []bool{true, false} {
+		for _, r := range rules {
+			if r.Context.IsEmpty() == contextual || seen[r.To] || !r.Context.Matches(rs, start, end) {
+				continue
+			}
+			seen[r.To] = true
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+type partialCandidate struct {
+	output   string
+	cost     int
+	segments []Segment
+}
+
+// add adds a candidate to the list, keeping the list sorted by cost, without duplicate outputs, and with at most max items
+func addCandidate(list []partialCandidate, c partialCandidate, max int) []partialCandidate {
+	for i, p := range list {
+		if p.output == c.output {
+			if c.cost >= p.cost {
+				return list
+			}
+			list = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	i := sort.Search(len(list), func(i int) bool { return list[i].cost > c.cost })
+	list = append(list, partialCandidate{})
+	copy(list[i+1:], list[i:])
+	list[i] = c
+	if len(list) > max {
+		list = list[:max]
+	}
+	return list
+}
+
+// Candidates converts the input string, and returns at most max candidates, lowest cost first. An error (*Error) is returned if any input character isn't covered by a rule or accepted as a common char, unless handled by the converter's unknown character policy.
+func (c CandidateConverter) Candidates(s string, max int) ([]Candidate, error) {
+	if max < 1 {
+		max = 1
+	}
+	rs := []rune(s)
+	offsets := runeOffsets(s)
+	unmapped := []int{}
+	// best[i] holds the best candidates for the first i runes of the input
+	best := make([][]partialCandidate, len(rs)+1)
+	best[0] = []partialCandidate{{}}
+	extend := func(i, n int, to string, rule Rule, cost int) {
+		for _, p := range best[i] {
+			segs := make([]Segment, len(p.segments), len(p.segments)+1)
+			copy(segs, p.segments)
+			segs = append(segs, Segment{InputStart: offsets[i], InputEnd: offsets[i+n], OutputStart: len(p.output), OutputEnd: len(p.output) + len(to), Rule: rule})
+			best[i+n] = addCandidate(best[i+n], partialCandidate{output: p.output + to, cost: p.cost + cost, segments: segs}, max)
+		}
+	}
+	// a character is covered if it is part of an input substring matching a rule
+	covered := make([]bool, len(rs))
+	for i := range rs {
+		for n := 1; n <= c.maxLen && i+n <= len(rs); n++ {
+			if len(c.alternatives(rs, i, i+n)) > 0 {
+				for j := i; j < i+n; j++ {
+					covered[j] = true
+				}
+			}
+		}
+	}
+	stuck := []int{}
+	for i := 0; i < len(rs); i++ {
+		if len(best[i]) == 0 {
+			continue
+		}
+		longest := 0
+		for n := c.maxLen; n > 0; n-- {
+			if i+n > len(rs) {
+				continue
+			}
+			alts := c.alternatives(rs, i, i+n)
+			if len(alts) == 0 {
+				continue
+			}
+			shorter := 0
+			if longest == 0 {
+				longest = n
+			} else {
+				shorter = 1
+			}
+			for rank, r := range alts {
+				extend(i, n, r.To, r, rank+shorter)
+			}
+		}
+		if longest > 0 {
+			continue
+		}
+		if c.isCommonChar(rs[i]) {
+			extend(i, 1, string(rs[i]), Rule{}, 0)
+		} else if covered[i] {
+			// the character is only covered by rules starting earlier
+			stuck = append(stuck, i)
+		} else if out, ok := c.unknown.Handle(rs[i]); ok {
+			extend(i, 1, out, Rule{}, 0)
+		} else {
+			unmapped = append(unmapped, i)
+			extend(i, 1, string(rs[i]), Rule{}, 0)
+		}
+	}
+	if len(unmapped) > 0 {
+		return nil, NewError(s, unmapped)
+	}
+	if len(best[len(rs)]) == 0 {
+		return nil, NewError(s, stuck)
+	}
+	res := []Candidate{}
+	for _, p := range best[len(rs)] {
+		res = append(res, Candidate{Output: p.output, Cost: p.cost, Segments: p.segments})
+	}
+	return res, nil
+}
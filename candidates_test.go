@@ -0,0 +1,103 @@
+package translit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testCandidateConverter(t *testing.T) CandidateConverter {
+	data := `#class vowel aeiouy
+e	е
+e	э
+e	ё
+y	й	after vowel	{vowel}_
+y	ы
+y	й
+a	а
+i	и
+k	к
+s	с
+h	х
+sh	ш
+shch	щ
+ch	ч
+`
+	table, err := ParseTable("test", strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("didn't expect error here! got %v", err)
+	}
+	return NewCandidateConverter(table.Rules, func(r rune) bool { return r == ' ' })
+}
+
+func candidateOutputs(cands []Candidate) []string {
+	res := []string{}
+	for _, c := range cands {
+		res = append(res, c.Output)
+	}
+	return res
+}
+
+func TestCandidates(t *testing.T) {
+	c := testCandidateConverter(t)
+	for input, expect := range map[string][]string{
+		"e":     {"е", "э", "ё"},
+		"ay":    {"ай", "аы"},
+		"sy":    {"сы", "сй"},
+		"shchi": {"щи", "шчи", "схчи"},
+		"e ky":  {"е кы", "э кы", "е кй", "ё кы", "э кй", "ё кй"},
+	} {
+		res, err := c.Candidates(input, DefaultMaxCandidates)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if got := candidateOutputs(res); !reflect.DeepEqual(got, expect) {
+			t.Errorf(fsExpGot, expect, got)
+		}
+	}
+
+	res, err := c.Candidates("e ky", 2)
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	} else if got := candidateOutputs(res); !reflect.DeepEqual(got, []string{"е кы", "э кы"}) {
+		t.Errorf(fsExpGot, []string{"е кы", "э кы"}, got)
+	}
+}
+
+func TestCandidatesSegments(t *testing.T) {
+	c := testCandidateConverter(t)
+	res, err := c.Candidates("shchi", 1)
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+		return
+	}
+	if len(res) != 1 || len(res[0].Segments) != 2 {
+		t.Errorf(fsExpGot, "one candidate with two segments", res)
+		return
+	}
+	seg := res[0].Segments[0]
+	if got := []int{seg.InputStart, seg.InputEnd, seg.OutputStart, seg.OutputEnd}; !reflect.DeepEqual(got, []int{0, 4, 0, 2}) || seg.Rule.From != "shch" {
+		t.Errorf(fsExpGot, "shch -> щ [0 4 0 2]", seg)
+	}
+}
+
+func TestCandidatesUnknown(t *testing.T) {
+	c := testCandidateConverter(t)
+	_, err := c.Candidates("kax", 5)
+	e, ok := err.(*Error)
+	if !ok {
+		t.Errorf(fsExpGot, "*Error", err)
+		return
+	}
+	if e.Offset != 2 {
+		t.Errorf(fsExpGot, 2, e.Offset)
+	}
+
+	res, err := c.WithUnknownPolicy(UnknownPolicy{Action: UnknownReplace, Replacement: "?"}).Candidates("kax", 5)
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	} else if got := candidateOutputs(res); !reflect.DeepEqual(got, []string{"ка?"}) {
+		t.Errorf(fsExpGot, []string{"ка?"}, got)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/stts-se/translit/bel"
+	"github.com/stts-se/translit/internal/cli"
+)
+
+func main() {
+	p := cli.NewProcessor(bel.NewTranslit())
+	p.Flags(flag.CommandLine, false)
+	cli.ParseFlags("Transliteration from Belarusian to Latin script.")
+
+	if err := p.Run(flag.Args()); err != nil {
+		log.Fatalf("%v", err)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/stts-se/translit/bul"
+	"github.com/stts-se/translit/internal/cli"
+)
+
+func main() {
+	p := cli.NewProcessor(bul.NewTranslit())
+	p.Flags(flag.CommandLine, false)
+	cli.ParseFlags("Transliteration from Bulgarian to Latin script.")
+
+	if err := p.Run(flag.Args()); err != nil {
+		log.Fatalf("%v", err)
+	}
+}
@@ -1,86 +1,25 @@
 package main
 
 import (
-	"bufio"
 	"flag"
-	"fmt"
 	"log"
-	"os"
-	"path/filepath"
 
-	tr "github.com/stts-se/translit"
 	"github.com/stts-se/translit/far"
+	"github.com/stts-se/translit/internal/cli"
 )
 
-var echoInput, failOnError *bool
-
-func process(s string) {
-	s = tr.NFC(s)
-	res, err := far.Convert(s)
-	if err != nil {
-		if *failOnError {
-			log.Fatalf(fmt.Sprintf("%v", err))
-		} else {
-			fmt.Fprintf(os.Stderr, fmt.Sprintf("ERROR %s\t%v\n", s, err))
-			return
-		}
-	}
-	if *echoInput {
-		fmt.Printf("%s\t%s\n", s, res)
-	} else {
-		fmt.Printf("%s\n", res)
-	}
-}
-
 func main() {
-
-	cmdname := filepath.Base(os.Args[0])
-	echoInput = flag.Bool("e", false, "Echo input (default: false)")
-	failOnError = flag.Bool("f", false, "Fail on error (default: false)")
-	help := flag.Bool("h", false, "Print help and exit")
-
-	var printUsage = func() {
-		fmt.Fprintln(os.Stderr, "Transliteration from Farsi to Latin script.")
-		fmt.Fprintln(os.Stderr)
-		fmt.Fprintln(os.Stderr, "Usage:")
-		fmt.Fprintln(os.Stderr, cmdname+" <input file(s)>")
-		fmt.Fprintln(os.Stderr, cmdname+" <input string(s)>")
-		fmt.Fprintln(os.Stderr, "cat <input file(s)> | "+cmdname)
-		fmt.Fprintln(os.Stderr, "\nOptional flags:")
-		flag.PrintDefaults()
-	}
-
-	flag.Usage = func() {
-		printUsage()
-		os.Exit(0)
-	}
-
-	flag.Parse()
-
-	if *help { // if flag.NArg() < 1 {
-		printUsage()
-		os.Exit(0)
-	}
-
-	if len(flag.Args()) > 0 {
-		for _, arg := range flag.Args() {
-			if tr.IsFile(arg) {
-				lines, err := tr.ReadFile(arg)
-				if err != nil {
-					log.Fatalf("Couldn't read file: %v", err)
-				}
-				for _, line := range lines {
-					process(line)
-				}
-			} else {
-				process(arg)
-			}
-		}
-	} else {
-		scanner := bufio.NewScanner(os.Stdin)
-		for scanner.Scan() {
-			s := scanner.Text()
-			process(s)
-		}
+	system := far.EI
+	flag.Var(&system, "system", "Romanisation `system`: ei, finglish (ASCII), un2012, bgnpcgn or dmg")
+	hyphen := flag.Bool("hyphen", false, "Render the zero width non-joiner (morpheme boundary) as a hyphen")
+	p := cli.NewProcessor(nil)
+	p.Flags(flag.CommandLine, true)
+	cli.ParseFlags("Transliteration from Farsi to Latin script (and reverse, with ranked candidates for ambiguous Latin spellings). Use -system to select another romanisation system.")
+
+	t := far.NewTranslitSystem(system)
+	t.ZWNJHyphen = *hyphen
+	p.Transliterator = t
+	if err := p.Run(flag.Args()); err != nil {
+		log.Fatalf("%v", err)
 	}
 }
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/stts-se/translit/internal/cli"
+	"github.com/stts-se/translit/mkd"
+)
+
+func main() {
+	p := cli.NewProcessor(mkd.NewTranslit())
+	p.Flags(flag.CommandLine, true)
+	cli.ParseFlags("Transliteration from Macedonian Cyrillic to Latin script (and reverse).")
+
+	if err := p.Run(flag.Args()); err != nil {
+		log.Fatalf("%v", err)
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tr "github.com/stts-se/translit"
+	"github.com/stts-se/translit/internal/cli"
+
+	// register all schemes
+	_ "github.com/stts-se/translit/bel"
+	_ "github.com/stts-se/translit/buckwalter"
+	_ "github.com/stts-se/translit/bul"
+	_ "github.com/stts-se/translit/far"
+	_ "github.com/stts-se/translit/grc"
+	_ "github.com/stts-se/translit/mkd"
+	_ "github.com/stts-se/translit/rus"
+	_ "github.com/stts-se/translit/srp"
+	_ "github.com/stts-se/translit/tamil"
+	_ "github.com/stts-se/translit/ukr"
+)
+
+var cmdname = filepath.Base(os.Args[0])
+
+type command struct {
+	name string
+	desc string
+	run  func(args []string) error
+}
+
+var commands = []command{
+	{name: "convert", desc: "Convert input using a transliteration scheme", run: convert},
+	{name: "list", desc: "List available transliteration schemes", run: list},
+	{name: "table", desc: "Print the mapping table of a transliteration scheme", run: table},
+	{name: "lint", desc: "Check a mapping table for duplicate, unreachable and ambiguous rules", run: lint},
+	{name: "verify", desc: "Verify round trip conversion of a corpus, using a reversible scheme", run: verify},
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Transliteration between scripts, using any of the available schemes.")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintf(os.Stderr, "%s <command> [flags] <args>\n", cmdname)
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.desc)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for help on a command.\n", cmdname)
+}
+
+func list(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	verbose := fs.Bool("v", false, "Verbose output (print description and references)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "List available transliteration schemes.\n\nUsage:\n%s list [flags]\n\nOptional flags:\n", cmdname)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, s := range tr.List() {
+		reversible := ""
+		if t, err := tr.Lookup(s.ID); err == nil && tr.IsReversible(t) {
+			reversible = "reversible"
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\n", s.ID, s.Source, s.Target, reversible)
+		if *verbose {
+			fmt.Printf("\t%s\n", s.Description)
+			for _, ref := range s.References {
+				fmt.Printf("\t%s\n", ref)
+			}
+		}
+	}
+	return nil
+}
+
+func table(args []string) error {
+	fs := flag.NewFlagSet("table", flag.ExitOnError)
+	scheme := fs.String("scheme", "", "Transliteration scheme ID (required, see the list command)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Print the mapping table of a transliteration scheme, in mapping table file format.\n\nUsage:\n%s table -scheme <id>\n\nFlags:\n", cmdname)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *scheme == "" {
+		fs.Usage()
+		return fmt.Errorf("no scheme specified")
+	}
+	t, err := tr.Lookup(*scheme)
+	if err != nil {
+		return err
+	}
+	ext, ok := t.(tr.Extensible)
+	if !ok {
+		return fmt.Errorf("scheme %s has no mapping table", *scheme)
+	}
+	return ext.Table().Write(os.Stdout)
+}
+
+func lint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	scheme := fs.String("scheme", "", "Transliteration scheme ID, to check the built-in table of the scheme")
+	reverse := fs.Bool("r", false, "Check that the rules can be used for reverse conversion (default: true for reversible schemes)")
+	script := fs.String("script", "", "ISO 15924 code of the source script, e.g. Cyrl, to check for uncovered letters in the Unicode block of the script (default: check the alphabet of the scheme source language)")
+	nfd := fs.Bool("nfd", false, "The tables are written for decomposed (NFD) input (default: true for schemes decomposing the input)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Check mapping tables for duplicate, unreachable and ambiguous rules, and uncovered characters of the source script.\n\nUsage:\n%s lint -scheme <id>\n%s lint [flags] <table file(s)>\n\nFlags:\n", cmdname, cmdname)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *scheme == "" && fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no scheme or table file specified")
+	}
+	nIssues := 0
+	lintTable := func(t tr.Table, opts tr.LintOptions) {
+		for _, issue := range tr.LintTable(t, opts) {
+			fmt.Printf("%s\t%s\n", t.Name, issue)
+			nIssues++
+		}
+	}
+	if *scheme != "" {
+		t, err := tr.Lookup(*scheme)
+		if err != nil {
+			return err
+		}
+		ext, ok := t.(tr.Extensible)
+		if !ok {
+			return fmt.Errorf("scheme %s has no mapping table", *scheme)
+		}
+		opts := tr.LintOptions{Reverse: *reverse || isStrictlyReversible(t), Script: *script, Decomposed: *nfd || tr.IsDecomposed(t)}
+		if opts.Script == "" {
+			opts.Alphabet = tr.Alphabet(t.Scheme().Source)
+		}
+		table := ext.Table()
+		table.Name = *scheme
+		lintTable(table, opts)
+	}
+	for _, fn := range fs.Args() {
+		table, err := tr.ReadTable(fn)
+		if err != nil {
+			return err
+		}
+		lintTable(table, tr.LintOptions{Reverse: *reverse, Script: *script, Decomposed: *nfd})
+	}
+	if nIssues > 0 {
+		return fmt.Errorf("found %d issue(s)", nIssues)
+	}
+	return nil
+}
+
+// isStrictlyReversible returns true for reversible schemes converting each target string back into a single source string. Schemes returning ranked candidates are ambiguous by design.
+func isStrictlyReversible(t tr.Transliterator) bool {
+	_, candidates := t.(tr.CandidateReverser)
+	return tr.IsReversible(t) && !candidates
+}
+
+func verify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	scheme := fs.String("scheme", "", "Transliteration scheme ID (required, see the list command)")
+	reverse := fs.Bool("r", false, "Verify input in the target script (target to source script and back)")
+	tableFile := fs.String("table", "", "Mapping table `file` with rules to add to (or override) the built-in table")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Verify round trip conversion (source to target script and back) of each input line, and print the lines that fail.\n\nUsage:\n%s verify -scheme <id> <input file(s)>\ncat <input file(s)> | %s verify -scheme <id>\n\nFlags:\n", cmdname, cmdname)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *scheme == "" {
+		fs.Usage()
+		return fmt.Errorf("no scheme specified")
+	}
+	t, err := tr.Lookup(*scheme)
+	if err != nil {
+		return err
+	}
+	if *tableFile != "" {
+		if t, err = cli.Extend(t, *tableFile); err != nil {
+			return err
+		}
+	}
+	r, ok := t.(tr.Reverser)
+	if !ok {
+		return fmt.Errorf("scheme %s is not reversible", t.Scheme())
+	}
+	verifyFunc := func(s string) error { return tr.VerifyRoundTrip(r, s) }
+	if *reverse {
+		verifyFunc = func(s string) error { return tr.VerifyReverseRoundTrip(r, s) }
+	}
+
+	nTotal, nFailed := 0, 0
+	verifyReader := func(source string, in io.Reader) error {
+		failures, n, err := tr.VerifyCorpus(in, verifyFunc)
+		for _, f := range failures {
+			fmt.Printf("%s:%d\t%s\t%v\n", source, f.Line, f.Input, f.Err)
+		}
+		nTotal += n
+		nFailed += len(failures)
+		return err
+	}
+	if fs.NArg() == 0 {
+		if err := verifyReader("<stdin>", os.Stdin); err != nil {
+			return err
+		}
+	}
+	for _, fn := range fs.Args() {
+		in, err := tr.OpenFile(fn)
+		if err != nil {
+			return err
+		}
+		err = verifyReader(filepath.Base(fn), in)
+		in.Close()
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "VERIFIED % 7d\n", nTotal)
+	fmt.Fprintf(os.Stderr, "  FAILED % 7d\n", nFailed)
+	if nFailed > 0 {
+		return fmt.Errorf("round trip failed for %d of %d inputs", nFailed, nTotal)
+	}
+	return nil
+}
+
+func convert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	scheme := fs.String("scheme", "", "Transliteration scheme ID (required, see the list command)")
+	p := cli.NewProcessor(nil)
+	p.Flags(fs, true)
+	fs.BoolVar(&p.PrintSource, "b", false, "Print input file basename (or <stdin>) first on each output line (default: false)")
+	fs.BoolVar(&p.Stats, "stats", false, "Print processing statistics to stderr (default: false)")
+	fs.Usage = cli.Usage(fs, cmdname+" convert -scheme <id>", "Convert input using a transliteration scheme.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *scheme == "" {
+		fs.Usage()
+		return fmt.Errorf("no scheme specified")
+	}
+	t, err := tr.Lookup(*scheme)
+	if err != nil {
+		return err
+	}
+	p.Transliterator = t
+	return p.Run(fs.Args())
+}
+
+func main() {
+	if len(os.Args) < 2 || strings.HasPrefix(os.Args[1], "-h") || os.Args[1] == "help" {
+		printUsage()
+		os.Exit(0)
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			if err := c.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	printUsage()
+	fmt.Fprintf(os.Stderr, "\nUnknown command: %s\n", os.Args[1])
+	os.Exit(1)
+}
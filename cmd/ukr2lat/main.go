@@ -0,0 +1,19 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/stts-se/translit/internal/cli"
+	"github.com/stts-se/translit/ukr"
+)
+
+func main() {
+	p := cli.NewProcessor(ukr.NewTranslit())
+	p.Flags(flag.CommandLine, false)
+	cli.ParseFlags("Transliteration from Ukrainian to Latin script.")
+
+	if err := p.Run(flag.Args()); err != nil {
+		log.Fatalf("%v", err)
+	}
+}
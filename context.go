@@ -0,0 +1,204 @@
+package translit
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Context is a condition on the input characters before (left) and after (right) the source string of a rule.
+//
+// A context is written as left_right, where each side is a sequence of:
+//
+//	#        word boundary
+//	{name}   any character in the named character class
+//	[chars]  any of the characters within brackets
+//	[^chars] any character except the ones within brackets
+//	c        the character c
+//
+// For example, #_ means word initial, _# word final, and {vowel}_{vowel} between vowels. Matching is case insensitive. Word characters are letters and combining marks, everything else is a word boundary (as are the start and end of the input).
+type Context struct {
+	left  []contextElem
+	right []contextElem
+	src   string
+}
+
+type contextElem struct {
+	boundary bool
+	chars    string // lower case
+	negate   bool
+}
+
+// ContextSeparator separates the left and right sides of a context
+const ContextSeparator = "_"
+
+// ParseContext parses a context string. The classes map holds the characters of each named character class.
+func ParseContext(s string, classes map[string]string) (Context, error) {
+	res := Context{src: s}
+	if s == "" {
+		return res, nil
+	}
+	parts := strings.Split(s, ContextSeparator)
+	if len(parts) != 2 {
+		return res, fmt.Errorf("invalid context '%s' (expected left%sright)", s, ContextSeparator)
+	}
+	var err error
+	if res.left, err = parseContextElems(parts[0], classes); err != nil {
+		return res, fmt.Errorf("invalid context '%s' : %v", s, err)
+	}
+	if res.right, err = parseContextElems(parts[1], classes); err != nil {
+		return res, fmt.Errorf("invalid context '%s' : %v", s, err)
+	}
+	return res, nil
+}
+
+// MustParseContext is like ParseContext, but panics on error
+func MustParseContext(s string, classes map[string]string) Context {
+	c, err := ParseContext(s, classes)
+	if err != nil {
+		panic(fmt.Sprintf("translit: %v", err))
+	}
+	return c
+}
+
+func parseContextElems(s string, classes map[string]string) ([]contextElem, error) {
+	res := []contextElem{}
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		switch rs[i] {
+		case '#':
+			res = append(res, contextElem{boundary: true})
+		case '{':
+			end := indexRune(rs, i, '}')
+			if end < 0 {
+				return res, fmt.Errorf("missing '}'")
+			}
+			name := string(rs[i+1 : end])
+			chars, ok := classes[name]
+			if !ok {
+				return res, fmt.Errorf("undefined character class '%s'", name)
+			}
+			res = append(res, contextElem{chars: strings.ToLower(chars)})
+			i = end
+		case '[':
+			end := indexRune(rs, i, ']')
+			if end < 0 {
+				return res, fmt.Errorf("missing ']'")
+			}
+			chars := string(rs[i+1 : end])
+			negate := strings.HasPrefix(chars, "^")
+			chars = strings.TrimPrefix(chars, "^")
+			if chars == "" {
+				return res, fmt.Errorf("empty character set")
+			}
+			res = append(res, contextElem{chars: strings.ToLower(chars), negate: negate})
+			i = end
+		default:
+			res = append(res, contextElem{chars: strings.ToLower(string(rs[i]))})
+		}
+	}
+	return res, nil
+}
+
+func indexRune(rs []rune, from int, r rune) int {
+	for i := from; i < len(rs); i++ {
+		if rs[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsEmpty returns true if the context has no conditions
+func (c Context) IsEmpty() bool {
+	return len(c.left) == 0 && len(c.right) == 0
+}
+
+func (c Context) String() string {
+	return c.src
+}
+
+// IsWordChar returns true for letters and combining marks
+func IsWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r)
+}
+
+func (e contextElem) matches(r rune) bool {
+	return strings.ContainsRune(e.chars, unicode.ToLower(r)) != e.negate
+}
+
+// Matches returns true if the context matches the input runes before position start, and from position end
+func (c Context) Matches(rs []rune, start, end int) bool {
+	pos := start
+	for k := len(c.left) - 1; k >= 0; k-- {
+		e := c.left[k]
+		if e.boundary {
+			if pos > 0 && IsWordChar(rs[pos-1]) {
+				return false
+			}
+			continue
+		}
+		if pos == 0 || !e.matches(rs[pos-1]) {
+			return false
+		}
+		pos--
+	}
+	pos = end
+	for _, e := range c.right {
+		if e.boundary {
+			if pos < len(rs) && IsWordChar(rs[pos]) {
+				return false
+			}
+			continue
+		}
+		if pos >= len(rs) || !e.matches(rs[pos]) {
+			return false
+		}
+		pos++
+	}
+	return true
+}
+
+// covers returns true if the context matches wherever the other context matches
+func (c Context) covers(other Context) bool {
+	if len(c.left) > len(other.left) || len(c.right) > len(other.right) {
+		return false
+	}
+	// the left side is matched from the source string outwards
+	for k := 1; k <= len(c.left); k++ {
+		if !c.left[len(c.left)-k].covers(other.left[len(other.left)-k]) {
+			return false
+		}
+	}
+	for k, e := range c.right {
+		if !e.covers(other.right[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// covers returns true if the element matches whatever the other element matches
+func (e contextElem) covers(other contextElem) bool {
+	if e.boundary || other.boundary {
+		return e.boundary && other.boundary
+	}
+	switch {
+	case !e.negate && !other.negate:
+		return containsAll(e.chars, other.chars)
+	case e.negate && !other.negate:
+		return !strings.ContainsAny(other.chars, e.chars)
+	case e.negate && other.negate:
+		return containsAll(other.chars, e.chars)
+	}
+	return false
+}
+
+func containsAll(s, chars string) bool {
+	for _, r := range chars {
+		if !strings.ContainsRune(s, r) {
+			return false
+		}
+	}
+	return true
+}
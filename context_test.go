@@ -0,0 +1,115 @@
+package translit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContextMatches(t *testing.T) {
+	classes := map[string]string{"vowel": "aeiou"}
+	tests := []struct {
+		context string
+		input   string
+		start   int
+		end     int
+		expect  bool
+	}{
+		{"#_", "sas", 0, 1, true},
+		{"#_", "sas", 2, 3, false},
+		{"#_", "a sa", 2, 3, true},
+		{"_#", "sas", 2, 3, true},
+		{"_#", "sas", 0, 1, false},
+		{"_#", "as, a", 1, 2, true},
+		{"{vowel}_{vowel}", "asa", 1, 2, true},
+		{"{vowel}_{vowel}", "ASA", 1, 2, true},
+		{"{vowel}_{vowel}", "ssa", 1, 2, false},
+		{"{vowel}_{vowel}", "as", 1, 2, false},
+		{"[^aeiou]_", "bsa", 1, 2, true},
+		{"[^aeiou]_", "asa", 1, 2, false},
+		{"#{vowel}_", "asa", 1, 2, true},
+		{"#{vowel}_", "basa", 2, 3, false},
+		{"i_#", "iy", 1, 2, true},
+		{"", "iy", 1, 2, true},
+	}
+	for _, test := range tests {
+		c, err := ParseContext(test.context, classes)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res := c.Matches([]rune(test.input), test.start, test.end); res != test.expect {
+			t.Errorf("for context '%s' and input '%s' [%d:%d]: expected %v, got %v", test.context, test.input, test.start, test.end, test.expect, res)
+		}
+	}
+}
+
+func TestParseContextErrors(t *testing.T) {
+	for _, s := range []string{"a", "a_b_c", "{undef}_", "{vowel_", "[]_", "[ab_"} {
+		if _, err := ParseContext(s, map[string]string{"vowel": "aeiou"}); err == nil {
+			t.Errorf("expected error for '%s'", s)
+		}
+	}
+}
+
+func TestConverterContext(t *testing.T) {
+	data := `#class vowel аеёиоуыэюя
+е	ye	word initial	#_
+е	ye	after vowel	{vowel}_
+е	e
+й	y
+й	j	word final, after vowel	{vowel}_#
+и	i
+ш	sh
+о	o
+к	k
+`
+	table, err := ParseTable("test", strings.NewReader(data))
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+		return
+	}
+	c := NewConverter(table.Rules, func(r rune) bool { return r == ' ' })
+	for input, expect := range map[string]string{
+		"ешиш":  "yeshish",
+		"шие":   "shiye",
+		"шеш":   "shesh",
+		"ий":    "ij",
+		"ийк":   "iyk",
+		"ой ий": "oj ij",
+	} {
+		res, err := c.Convert(input, true)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		}
+		if res != expect {
+			t.Errorf(fsExpGot, expect, res)
+		}
+	}
+}
+
+func TestTableContextRoundtrip(t *testing.T) {
+	data := `#class vowel aeiou
+s	z	voiced	{vowel}_{vowel}
+s	s
+`
+	table, err := ParseTable("test", strings.NewReader(data))
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+		return
+	}
+	var w strings.Builder
+	if err := table.Write(&w); err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	table2, err := ParseTable("test", strings.NewReader(w.String()))
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+		return
+	}
+	if len(table2.Rules) != 2 || table2.Rules[0].Context.String() != "{vowel}_{vowel}" || !table2.Rules[1].Context.IsEmpty() {
+		t.Errorf(fsExpGot, table.Rules, table2.Rules)
+	}
+	if table2.Classes["vowel"] != "aeiou" {
+		t.Errorf(fsExpGot, "aeiou", table2.Classes["vowel"])
+	}
+}
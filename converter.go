@@ -0,0 +1,90 @@
+package translit
+
+import (
+	"strings"
+)
+
+// Rule is a mapping from a source string to a target string, optionally restricted to a context
+type Rule struct {
+	From    string
+	To      string
+	Comment string
+	Context Context
+}
+
+// Converter converts strings using longest-match lookup of the mapping rules. Characters not covered by any rule are accepted as they are, if isCommonChar returns true for them.
+type Converter struct {
+	trie         *Trie
+	isCommonChar func(r rune) bool
+	unknown      UnknownPolicy
+}
+
+// NewConverter creates a converter for the mapping rules. If several rules have the same source string and context, the first one is used. Rules with a context take precedence over rules without context (see Trie.Match). The isCommonChar function may be nil.
+func NewConverter(rules []Rule, isCommonChar func(r rune) bool) Converter {
+	trie := NewTrie()
+	for _, r := range rules {
+		trie.AddRule(r)
+	}
+	if isCommonChar == nil {
+		isCommonChar = func(r rune) bool { return false }
+	}
+	return Converter{trie: trie, isCommonChar: isCommonChar}
+}
+
+// WithUnknownPolicy returns a copy of the converter, using the policy for characters that aren't covered by a rule or accepted as a common char, if requireAllMapped is true (see Convert)
+func (c Converter) WithUnknownPolicy(p UnknownPolicy) Converter {
+	c.unknown = p
+	return c
+}
+
+// Convert converts the input string. If requireAllMapped is true, an error (*Error) is returned if any input character isn't covered by a rule or accepted as a common char, unless the converter has an unknown character policy handling such characters (see WithUnknownPolicy). Otherwise, unmapped characters are copied to the output.
+func (c Converter) Convert(s string, requireAllMapped bool) (string, error) {
+	res, _, err := c.convert(s, requireAllMapped, false)
+	return res, err
+}
+
+// ConvertSegments is like Convert, but also returns the alignment of input and output, with one segment per rule applied or character copied
+func (c Converter) ConvertSegments(s string, requireAllMapped bool) (string, []Segment, error) {
+	return c.convert(s, requireAllMapped, true)
+}
+
+func (c Converter) convert(s string, requireAllMapped bool, align bool) (string, []Segment, error) {
+	rs := []rune(s)
+	var res strings.Builder
+	var segs []Segment
+	var unmapped []int
+	var offsets []int // byte offset of each rune
+	if align {
+		offsets = runeOffsets(s)
+	}
+	for i := 0; i < len(rs); {
+		var seg Segment
+		if align {
+			seg = Segment{InputStart: offsets[i], OutputStart: res.Len()}
+		}
+		if rule, n := c.trie.Match(rs, i); n > 0 {
+			res.WriteString(rule.To)
+			i += n
+			seg.Rule = rule
+		} else {
+			if !c.isCommonChar(rs[i]) && requireAllMapped {
+				if out, ok := c.unknown.Handle(rs[i]); ok {
+					res.WriteString(out)
+				} else {
+					unmapped = append(unmapped, i)
+				}
+			} else {
+				res.WriteRune(rs[i])
+			}
+			i++
+		}
+		if align {
+			seg.InputEnd, seg.OutputEnd = offsets[i], res.Len()
+			segs = append(segs, seg)
+		}
+	}
+	if len(unmapped) > 0 {
+		return "", nil, NewError(s, unmapped)
+	}
+	return res.String(), segs, nil
+}
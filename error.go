@@ -0,0 +1,58 @@
+package translit
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error is returned for input that contains characters not covered by the mapping rules. Use errors.As to access the details:
+//
+//	var e *translit.Error
+//	if errors.As(err, &e) {
+//		fmt.Println(e.Offset, e.Char.Name)
+//	}
+type Error struct {
+	Scheme   string      // Scheme ID, if known
+	Input    string      // Input string
+	Offset   int         // Rune offset of the first unmapped character
+	Rune     rune        // The first unmapped character
+	Char     UnicodeChar // Unicode details of the first unmapped character
+	Unmapped []int       // Rune offsets of all unmapped characters, in input order
+}
+
+// NewError creates an error for the input string, given the rune offsets of the unmapped characters. It returns nil if there are no unmapped characters.
+func NewError(input string, unmapped []int) *Error {
+	if len(unmapped) == 0 {
+		return nil
+	}
+	rs := []rune(input)
+	r := rs[unmapped[0]]
+	return &Error{
+		Input:    input,
+		Offset:   unmapped[0],
+		Rune:     r,
+		Char:     UnicodeInfo(string(r))[0],
+		Unmapped: unmapped,
+	}
+}
+
+func (e *Error) Error() string {
+	rest := string([]rune(e.Input)[e.Offset:])
+	msg := fmt.Sprintf("Couldn't convert '%s'\t%v\tin '%s'", rest, e.Char, e.Input)
+	if len(e.Unmapped) > 1 {
+		msg = fmt.Sprintf("%s (%d unmapped characters)", msg, len(e.Unmapped))
+	}
+	if e.Scheme != "" {
+		msg = fmt.Sprintf("%s: %s", e.Scheme, msg)
+	}
+	return msg
+}
+
+// WithScheme sets the scheme ID of the error, if it is an *Error (see errors.As). The error is returned.
+func WithScheme(err error, scheme Scheme) error {
+	var e *Error
+	if errors.As(err, &e) {
+		e.Scheme = scheme.ID
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package translit
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	c := NewConverter([]Rule{{From: "а", To: "a"}, {From: "б", To: "b"}}, func(r rune) bool { return r == ' ' })
+	_, err := c.Convert("аб аxбy", true)
+	if err == nil {
+		t.Errorf("expected error here")
+		return
+	}
+	err = WithScheme(fmt.Errorf("wrapped: %w", err), Scheme{ID: "xx-test"})
+
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Errorf(fsExpGot, "*Error", err)
+		return
+	}
+	if e.Scheme != "xx-test" {
+		t.Errorf(fsExpGot, "xx-test", e.Scheme)
+	}
+	if e.Offset != 4 || e.Rune != 'x' || e.Char.Code != "\\u0078" {
+		t.Errorf(fsExpGot, "4 'x' \\u0078", fmt.Sprintf("%d %q %s", e.Offset, e.Rune, e.Char.Code))
+	}
+	if !reflect.DeepEqual(e.Unmapped, []int{4, 6}) {
+		t.Errorf(fsExpGot, []int{4, 6}, e.Unmapped)
+	}
+	expect := "xx-test: Couldn't convert 'xбy'\t{x LATIN SMALL LETTER X \\u0078 Latin}\tin 'аб аxбy' (2 unmapped characters)"
+	if e.Error() != expect {
+		t.Errorf(fsExpGot, expect, e.Error())
+	}
+
+	if _, err := c.Convert("аб аxбy", false); err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if NewError("аб", nil) != nil {
+		t.Errorf("expected nil error for no unmapped characters")
+	}
+}
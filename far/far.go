@@ -1,77 +1,52 @@
 package far
 
 import (
-	"fmt"
+	_ "embed"
 	"regexp"
 	"strings"
+	"unicode"
 
 	tr "github.com/stts-se/translit"
 )
 
-type pair struct {
-	s1 string
-	s2 string
-}
-
-var maptable = []pair{ // https://en.wikipedia.org/wiki/Romanization_of_Persian EI 2012
-
-	// CONSONANTS
-	{s1: "\u0627", s2: "’"}, // TODO: not in the beginning of words
-	{s1: "\u0628", s2: "b"},
-	{s1: "\u067E", s2: "p"},
-	{s1: "\u062A", s2: "t"},
-	{s1: "\u062B", s2: "ṯ"},
-	{s1: "\u062C", s2: "j"},
-	{s1: "\u0686", s2: "č"},
-	{s1: "\u062D", s2: "ḥ"},
-	{s1: "\u062E", s2: "ḵ"},
-	{s1: "\u062F", s2: "d"},
-	{s1: "\u0630", s2: "ḏ"},
-	{s1: "\u0631", s2: "r"},
-	{s1: "\u0632", s2: "z"},
-	{s1: "\u0698", s2: "ž"},
-	{s1: "\u0633", s2: "s"},
-	{s1: "\u0634", s2: "š"},
-	{s1: "\u0635", s2: "ṣ"},
-	{s1: "\u0636", s2: "ż"},
-	{s1: "\u0637", s2: "ṭ"},
-	{s1: "\u0638", s2: "ẓ"},
-	{s1: "\u0639", s2: "‘"},
-	{s1: "\u063A", s2: "ḡ"},
-	{s1: "\u0641", s2: "f"},
-	{s1: "\u0642", s2: "ḳ"},
-	{s1: "\u06A9", s2: "k"},
-	{s1: "\u06AF", s2: "g"},
-	{s1: "\u0644", s2: "l"},
-	{s1: "\u0645", s2: "m"},
-	{s1: "\u0646", s2: "n"},
-	{s1: "\u0648", s2: "v"},
-	{s1: "\u0647", s2: "h"},
-	{s1: "\u0629", s2: "h"},
-	{s1: "\u06CC", s2: "y"},
-	{s1: "\u0621", s2: "’"},
-	{s1: "\u0624", s2: "’"},
-	{s1: "\u0626", s2: "’"},
-
-	// VOWELS
-	{s1: "\u064E", s2: "a"},
-	{s1: "\u064F", s2: "u"},
-	{s1: "\u0648\u064F", s2: "u"},
-	{s1: "\u0650", s2: "e"},
-	{s1: "\u064E\u0627", s2: "ā"},
-	{s1: "\u0622", s2: "ā"},
-	{s1: "\u064E\u06CC", s2: "ā"},
-	{s1: "\u06CC\u0670", s2: "ā"},
-	{s1: "\u064F\u0648", s2: "u"},
-	{s1: "\u0650\u06CC", s2: "i"},
-	{s1: "\u064E\u0648", s2: "ow"},
-	{s1: "\u064E\u06CC", s2: "ey"}, // duplicate key
-	{s1: "\u064E\u06CC", s2: "–e"}, // duplicate key
-	{s1: "\u06C0", s2: "–ye"},
-
-	// MISC
-	//{s1: "\u200c", s2: ""}, // zero width non-joiner
-}
+//go:embed tables/ei.tsv
+var maptableData string
+
+var maptable = tr.MustParseTable("ei.tsv", maptableData)
+
+//go:embed tables/ei-rev.tsv
+var revTableData string
+
+// Latin (Encyclopaedia Iranica) to Persian, with ranked alternatives
+var revTable = tr.MustParseTable("ei-rev.tsv", revTableData)
+
+//go:embed tables/finglish.tsv
+var finglishData string
+
+//go:embed tables/finglish-rev.tsv
+var finglishRevData string
+
+//go:embed tables/un2012.tsv
+var un2012Data string
+
+//go:embed tables/bgnpcgn.tsv
+var bgnPCGNData string
+
+//go:embed tables/dmg.tsv
+var dmgData string
+
+// https://en.wikipedia.org/wiki/Romanization_of_Persian
+var un2012 = tr.MustParseTable("un2012.tsv", un2012Data)
+
+// https://en.wikipedia.org/wiki/BGN/PCGN_romanization_of_Persian
+var bgnPCGN = tr.MustParseTable("bgnpcgn.tsv", bgnPCGNData)
+
+// https://de.wikipedia.org/wiki/DMG-Umschrift
+var dmg = tr.MustParseTable("dmg.tsv", dmgData)
+
+// https://en.wikipedia.org/wiki/Fingilish
+var finglish = tr.MustParseTable("finglish.tsv", finglishData)
+var finglishRev = tr.MustParseTable("finglish-rev.tsv", finglishRevData)
 
 var commonCharsRE = regexp.MustCompile("[A-Za-z0-9()@΄$ï*'_]")
 
@@ -81,73 +56,296 @@ var commonChars = map[string]bool{
 	",": true,
 	"(": true,
 	")": true,
-	//"\u200c": true, // zero width non-joiner
 }
 
+// zero width non-joiner, a morpheme boundary within a word (mapped in the tables)
+const zwnj = "\u200c"
+
+// kasra, marking an ezafe on the final letter of a word
+const kasra = '\u0650'
+
+// hamza above, marking an ezafe after ه (as ۀ)
+const hamzaAbove = "\u0654"
+
 var echoInput, failOnError *bool
 
-func Convert(s string) (string, error) {
-	s = tr.NFC(s)
-
-	// for _, re := range mapRegexps {
-	// 	s = re.from.ReplaceAllString(s, re.to)
-	// }
-
-	intAll := []pair{}
-	for _, p := range maptable {
-		intAll = append(intAll, p)
-		for _, case1 := range tr.UpcaseInitials(p.s1) {
-			for _, case2 := range tr.UpcaseInitials(p.s2) {
-				intAll = append(intAll, pair{s1: case1, s2: case2})
-			}
-		}
+// System is a romanisation system
+type System int
 
-		// intAll = append(intAll, p)
-		// intAll = append(intAll, pair{s1: tr.UpcaseInitial(p.s1), s2: tr.UpcaseInitial(p.s2)})
-		// intAll = append(intAll, pair{s1: tr.Upcase(p.s1), s2: tr.Upcase(p.s2)})
-		// if len([]rune(p.s1)) > 2 {
-		// 	intAll = append(intAll, pair{s1: tr.UpcaseTwoInitials(p.s1), s2: tr.UpcaseInitial(p.s2)})
-		// }
-	}
-	res, err := innerConvert(intAll, s, true)
+const (
+	// EI is the Encyclopaedia Iranica system (default)
+	EI System = iota
+	// Finglish is an ASCII only spelling, reversible
+	Finglish
+	// UN2012 is the UN approved Iranian system (2012), for geographic names
+	UN2012
+	// BGNPCGN is the BGN/PCGN (1958) system, for geographic names
+	BGNPCGN
+	// DMG is the DMG (1969) system, in the style of DIN 31635, for academic use
+	DMG
+)
+
+var systemNames = tr.SystemNames{"ei", "finglish", "un2012", "bgnpcgn", "dmg"}
+
+func (s System) String() string {
+	return systemNames.Name(int(s))
+}
+
+// Set implements the flag.Value interface, for the system names ei, finglish, un2012, bgnpcgn and dmg
+func (s *System) Set(name string) error {
+	i, err := systemNames.Index(name)
 	if err != nil {
-		return "", err
+		return err
 	}
-	return res, nil
-}
-
-func innerConvert(chsAll []pair, s string, requireAllMapped bool) (string, error) {
-	sOrig := s
-	res := []string{}
-	for len(s) > 0 {
-		sStart := s
-		head := string([]rune(s)[0])
-		for _, p := range chsAll {
-			if strings.HasPrefix(s, p.s1) {
-				res = append(res, p.s2)
-				s = strings.TrimPrefix(s, p.s1)
-				break
-			}
-			// check for common chars
-			if _, ok := commonChars[head]; ok {
-				res = append(res, head)
-				s = strings.TrimPrefix(s, head)
-				break
-			}
-			if commonCharsRE.MatchString(head) {
-				res = append(res, head)
-				s = strings.TrimPrefix(s, head)
-				break
+	*s = System(i)
+	return nil
+}
+
+// Translit is a transliterator for one of the romanisation systems (see NewTranslitSystem)
+type Translit struct {
+	tr.SystemTranslit
+	ZWNJHyphen bool // Render the zero width non-joiner (a morpheme boundary, as in mi-ravam) as a hyphen, instead of dropping it. The Finglish system always uses a hyphen.
+}
+
+// NewTranslit creates a transliterator for the default system (EI)
+func NewTranslit() Translit {
+	return Translit{SystemTranslit: systems[EI]}
+}
+
+// NewTranslitSystem creates a transliterator for a romanisation system (EI for unknown values)
+func NewTranslitSystem(system System) Translit {
+	if system < 0 || int(system) >= len(systems) {
+		return NewTranslit()
+	}
+	return Translit{SystemTranslit: systems[system]}
+}
+
+var _ tr.Extensible = Translit{}
+var _ tr.UnknownHandler = Translit{}
+var _ tr.CandidateReverser = Translit{}
+
+var eiScheme = tr.Scheme{
+	ID:          "fa-Latn-x-ei",
+	Source:      "fa-Arab",
+	Target:      "fa-Latn",
+	Description: "Persian to Latin script, Encyclopaedia Iranica (2012)",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Romanization_of_Persian",
+	},
+}
+
+var finglishScheme = tr.Scheme{
+	ID:          "fa-Latn-x-finglish",
+	Source:      "fa-Arab",
+	Target:      "fa-Latn",
+	Description: "Persian to ASCII (Finglish), reversible",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Fingilish",
+	},
+}
+
+var un2012Scheme = tr.Scheme{
+	ID:          "fa-Latn-x-un2012",
+	Source:      "fa-Arab",
+	Target:      "fa-Latn",
+	Description: "Persian to Latin script, UN approved Iranian system (2012)",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Romanization_of_Persian",
+	},
+}
+
+var bgnPCGNScheme = tr.Scheme{
+	ID:          "fa-Latn-x-bgnpcgn",
+	Source:      "fa-Arab",
+	Target:      "fa-Latn",
+	Description: "Persian to Latin script, BGN/PCGN (1958)",
+	References: []string{
+		"https://en.wikipedia.org/wiki/BGN/PCGN_romanization_of_Persian",
+	},
+}
+
+var dmgScheme = tr.Scheme{
+	ID:          "fa-Latn-x-dmg",
+	Source:      "fa-Arab",
+	Target:      "fa-Latn",
+	Description: "Persian to Latin script, DMG (1969, DIN 31635 style)",
+	References: []string{
+		"https://de.wikipedia.org/wiki/DMG-Umschrift",
+	},
+}
+
+func init() {
+	for i, s := range systems {
+		system := System(i)
+		tr.Register(s.Scheme(), func() tr.Transliterator { return NewTranslitSystem(system) })
+	}
+}
+
+// Transliterate converts the input string from Persian to Latin script. An ezafe is written -e or -ye (depending on the system) if it is marked in the input, by a kasra on the final letter of the word (see MarkEzafe), by ۀ (or ه with hamza above), or by a ye after a zero width non-joiner. The Input of the result is the normalised input string (see normalise).
+func (translit Translit) Transliterate(s string) (tr.Result, error) {
+	res, err := translit.SystemTranslit.Transliterate(s)
+	if err == nil && translit.ZWNJHyphen {
+		res = hyphenateZWNJ(res)
+	}
+	return res, err
+}
+
+// hyphenateZWNJ writes a hyphen for each zero width non-joiner dropped by the conversion, and updates the output offsets of the segments
+func hyphenateZWNJ(res tr.Result) tr.Result {
+	var output strings.Builder
+	segs := []tr.Segment{}
+	for _, seg := range res.Segments {
+		s := res.Output[seg.OutputStart:seg.OutputEnd]
+		if seg.Rule.From == zwnj && s == "" {
+			s = "-"
+		}
+		seg.OutputStart = output.Len()
+		output.WriteString(s)
+		seg.OutputEnd = output.Len()
+		segs = append(segs, seg)
+	}
+	res.Output, res.Segments = output.String(), segs
+	return res
+}
+
+// MarkEzafe returns the input string with an ezafe marked on the words with the given indices (counting from 0, words separated by white space), by adding a kasra after the final letter of each word. This is intended for callers that know where the ezafe is, since it is usually not written. Words already ending in a kasra, ۀ or a hamza above are left as they are.
+func MarkEzafe(s string, words ...int) string {
+	marked := map[int]bool{}
+	for _, w := range words {
+		marked[w] = true
+	}
+	rs := []rune(s)
+	insert := map[int]bool{} // insert a kasra before these positions
+	word := -1
+	last := -1 // position after the final letter (and its marks) of the current word
+	for i := 0; i <= len(rs); i++ {
+		if i == len(rs) || unicode.IsSpace(rs[i]) {
+			if last >= 0 && marked[word] {
+				insert[last] = true
 			}
+			last = -1
+			continue
+		}
+		if i == 0 || unicode.IsSpace(rs[i-1]) {
+			word++
+		}
+		switch {
+		case rs[i] == kasra || rs[i] == 'ۀ' || string(rs[i]) == hamzaAbove:
+			last = -1
+		case unicode.IsLetter(rs[i]):
+			last = i + 1
+		case unicode.IsMark(rs[i]) && last == i:
+			last = i + 1
 		}
-		if s == sStart { // nothing found to map for this prefix
-			if requireAllMapped {
-				return "", fmt.Errorf("Couldn't convert '%s'\t%v\tin '%s'", s, tr.UnicodeInfo(s)[0], sOrig)
-			} else if s == sStart {
-				res = append(res, head)
-				s = strings.TrimPrefix(s, head)
+	}
+	var res strings.Builder
+	for i, r := range rs {
+		if insert[i] {
+			res.WriteRune(kasra)
+		}
+		res.WriteRune(r)
+	}
+	if insert[len(rs)] {
+		res.WriteRune(kasra)
+	}
+	return res.String()
+}
+
+// normalise composes (NFC) the input string, and replaces ه followed by a hamza above, which isn't composed, with ۀ
+func normalise(s string) string {
+	return strings.ReplaceAll(tr.NFC(s), "ه"+hamzaAbove, "ۀ")
+}
+
+func isCommonChar(r rune) bool {
+	return commonChars[string(r)] || commonCharsRE.MatchString(string(r))
+}
+
+// Latin letters and signs are not common characters in reverse conversion
+func isRevCommonChar(r rune) bool {
+	return commonChars[string(r)] || unicode.IsDigit(r)
+}
+
+func buildRules(table tr.Table) []tr.Rule {
+	res := []tr.Rule{}
+	for _, r := range table.Rules {
+		res = append(res, r)
+		for _, case1 := range tr.UpcaseInitials(r.From) {
+			for _, case2 := range tr.UpcaseInitials(r.To) {
+				upcased := r
+				upcased.From, upcased.To = case1, case2
+				res = append(res, upcased)
 			}
 		}
 	}
-	return strings.Join(res, ""), nil
+	return res
+}
+
+// buildRevRules adds title case versions of the Latin source strings of the reverse rules
+func buildRevRules(rules []tr.Rule) []tr.Rule {
+	res := []tr.Rule{}
+	for _, r := range rules {
+		res = append(res, r)
+		if title := tr.UpcaseInitial(r.From); title != r.From {
+			upcased := r
+			upcased.From = title
+			res = append(res, upcased)
+		}
+	}
+	return res
+}
+
+// buildFinglishRevRules returns the rules of the Finglish rev table, followed by the reversed Finglish rules (the first rule wins for Latin spellings shared by several rules)
+func buildFinglishRevRules(rules []tr.Rule) []tr.Rule {
+	res := append([]tr.Rule{}, finglishRev.Rules...)
+	for _, r := range rules {
+		res = append(res, tr.Rule{From: r.To, To: r.From})
+	}
+	return buildRevRules(res)
+}
+
+// newSystem creates a transliterator for a system with title case rules added by buildRules
+func newSystem(scheme tr.Scheme, table tr.Table) tr.SystemTranslit {
+	return tr.NewSystemTranslit(tr.SystemConfig{Scheme: scheme, Table: table, Rules: buildRules, IsCommonChar: isCommonChar, Normalise: normalise})
+}
+
+// newEISystem creates the Encyclopaedia Iranica transliterator. Since several Persian letters may have the same Latin spelling (e.g. z for ز, ذ, ض and ظ), reverse conversion returns ranked candidates, written without vowel marks. Latin spellings without diacritics are accepted (e.g. s for س, ص and ث).
+func newEISystem() tr.SystemTranslit {
+	return tr.NewSystemTranslit(tr.SystemConfig{Scheme: eiScheme, Table: maptable, Rules: buildRules, IsCommonChar: isCommonChar, Normalise: normalise,
+		RevTable: &revTable, RevTableRules: func(t tr.Table) []tr.Rule { return buildRevRules(t.Rules) }, RevIsCommonChar: isRevCommonChar})
+}
+
+// newFinglishSystem creates the Finglish transliterator, using the rules of the table as they are, since the Latin output is always lower case
+func newFinglishSystem() tr.SystemTranslit {
+	return tr.NewSystemTranslit(tr.SystemConfig{Scheme: finglishScheme, Table: finglish, IsCommonChar: isCommonChar, Normalise: normalise,
+		RevRules: buildFinglishRevRules, RevIsCommonChar: isRevCommonChar})
+}
+
+// systems holds the transliterator of each System
+var systems = []tr.SystemTranslit{
+	EI:       newEISystem(),
+	Finglish: newFinglishSystem(),
+	UN2012:   newSystem(un2012Scheme, un2012),
+	BGNPCGN:  newSystem(bgnPCGNScheme, bgnPCGN),
+	DMG:      newSystem(dmgScheme, dmg),
+}
+
+// Extend returns a copy of the transliterator, with the rules of the input table added to the mapping table. Rules are expected in lower case, upper case versions are added automatically (except for Finglish, which is always lower case). The reverse conversion is updated too: Finglish is rebuilt from the merged table, and for Encyclopaedia Iranica, the reversed rules of the input table are ranked before the built-in candidates.
+func (translit Translit) Extend(t tr.Table) (tr.Transliterator, error) {
+	ext, err := translit.SystemTranslit.Extend(t)
+	if err != nil {
+		return nil, err
+	}
+	translit.SystemTranslit = ext.(tr.SystemTranslit)
+	return translit, nil
+}
+
+// WithUnknownPolicy returns a copy of the transliterator, using the policy for unknown input characters
+func (translit Translit) WithUnknownPolicy(p tr.UnknownPolicy) tr.Transliterator {
+	translit.SystemTranslit = translit.SystemTranslit.WithUnknownPolicy(p).(tr.SystemTranslit)
+	return translit
+}
+
+func Convert(s string) (string, error) {
+	res, err := NewTranslit().Transliterate(s)
+	return res.Output, err
 }
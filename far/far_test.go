@@ -0,0 +1,266 @@
+package far
+
+import (
+	"testing"
+
+	tr "github.com/stts-se/translit"
+)
+
+var fsExpGot = "Expected: %v got: %v"
+
+func TestFinglish(t *testing.T) {
+	tlit := NewTranslitSystem(Finglish)
+	for input, expect := range map[string]string{
+		"ایران":         "iran",
+		"سلام خوبی؟":    "slam khubi?",
+		"که سه مزه نگه": "ke se mze nge",
+		"کهنه":          "k_hne",
+		"ایالت اول":     "ialt ul",
+		"عید مبارک":     "'yd mbark",
+		"مسحور طی":      "ms_h2vr t2y",
+		"شیء اجزاء":     "shi` ajza`",
+		"مُحَمَّد":      "m^oh2^am^a~d",
+	} {
+		res, err := tlit.Transliterate(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+		if err := tr.VerifyRoundTrip(tlit, input); err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		}
+	}
+
+	res, err := tlit.Reverse("Iran")
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	} else if res.Output != "ایران" {
+		t.Errorf(fsExpGot, "ایران", res.Output)
+	}
+}
+
+func TestReverse(t *testing.T) {
+	tlit := NewTranslit()
+	for input, expect := range map[string][]string{
+		"Tehrān": {"تهران", "طهران"},
+		"Šīrāz":  {"شیراز", "شیراذ", "شیراض", "شیراظ"},
+		"ketāb":  {"کتاب"},
+		"Mašhad": {"مشهد"},
+	} {
+		res, err := tlit.Revert(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		for i, s := range expect {
+			if i >= len(res) || res[i] != s {
+				t.Errorf(fsExpGot, expect, res)
+				break
+			}
+		}
+	}
+}
+
+func TestSystems(t *testing.T) {
+	for system, tests := range map[System]map[string]string{
+		UN2012: {
+			"شیراز":       "shirāz",
+			"خانه":        "khāne",
+			"غلام عباس":   "qlām ʼbās",
+			"شاهنامۀ":     "shāhnāme-ye",
+			"کتاب و دفتر": "ktāb va dftr",
+		},
+		BGNPCGN: {
+			"شیراز":     "shīrāz",
+			"خانه":      "khāneh",
+			"غلام عباس": "ghlām ‘bās",
+			"شاهنامۀ":   "shāhnāmeh-ye",
+			"ایران":     "īrān",
+		},
+		DMG: {
+			"شیراز":     "šīrāz",
+			"خانه":      "ḫāna",
+			"غلام عباس": "ġlām ʿbās",
+			"شاهنامۀ":   "šāhnāma-yi",
+			"ثَروَت":    "s\u0331arvat",
+			"کِتابِ":    "kitāb-i",
+		},
+	} {
+		tlit := NewTranslitSystem(system)
+		for input, expect := range tests {
+			res, err := tlit.Transliterate(input)
+			if err != nil {
+				t.Errorf("didn't expect error here! got %v", err)
+				continue
+			}
+			if res.Output != expect {
+				t.Errorf(fsExpGot, expect, res.Output)
+			}
+		}
+		if tlit.Reversible() {
+			t.Errorf(fsExpGot, false, true)
+		}
+	}
+}
+
+func TestZWNJ(t *testing.T) {
+	for _, test := range []struct {
+		tlit   Translit
+		input  string
+		expect string
+	}{
+		{NewTranslitSystem(UN2012), "می‌روم خانه‌ها", "mirum khānehā"},
+		{Translit{SystemTranslit: systems[UN2012], ZWNJHyphen: true}, "می‌روم خانه‌ها", "mi-rum khāne-hā"},
+		{Translit{SystemTranslit: systems[EI], ZWNJHyphen: true}, "می‌روم", "my-rvm"},
+		{NewTranslitSystem(Finglish), "می‌روم", "mi-rum"},
+	} {
+		res, err := test.tlit.Transliterate(test.input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != test.expect {
+			t.Errorf(fsExpGot, test.expect, res.Output)
+		}
+		if last := res.Segments[len(res.Segments)-1]; last.OutputEnd != len(res.Output) {
+			t.Errorf(fsExpGot, len(res.Output), last.OutputEnd)
+		}
+	}
+}
+
+func TestEzafe(t *testing.T) {
+	for system, tests := range map[System]map[string]string{
+		EI: {
+			MarkEzafe("کتاب من", 0): "kt’b–e mn",
+			"خانه‌ی من":             "ḵ’nh–ye mn",
+			"خانۀ من":               "ḵ’n–ye mn",
+			"خانهٔ من":              "ḵ’n–ye mn",
+		},
+		UN2012: {
+			MarkEzafe("کتاب من", 0):   "ktāb-e mn",
+			MarkEzafe("خانه بزرگ", 0): "khāne-ye bzrg",
+			MarkEzafe("دریا آبی", 0):  "dryā-ye ābi",
+			"خانۀ من":                 "khāne-ye mn",
+			"خانه‌ی من":               "khāne-ye mn",
+			MarkEzafe("شاه ایران", 0): "shāh-e irān",
+		},
+		BGNPCGN: {
+			MarkEzafe("کتاب من", 0):   "ktāb-e mn",
+			MarkEzafe("خانه بزرگ", 0): "khāneh-ye bzrg",
+		},
+		DMG: {
+			MarkEzafe("کتاب من", 0):   "ktāb-i mn",
+			MarkEzafe("خانه بزرگ", 0): "ḫāna-yi bzrg",
+			"خانهٔ من":                "ḫāna-yi mn",
+		},
+		Finglish: {
+			MarkEzafe("کتاب من", 0): "ktab^e mn",
+			"خانه‌ی من":             "khane-y mn",
+			"خانۀ من":               "khane^e mn",
+			"خانهٔ من":              "khane^e mn",
+		},
+	} {
+		tlit := NewTranslitSystem(system)
+		for input, expect := range tests {
+			res, err := tlit.Transliterate(input)
+			if err != nil {
+				t.Errorf("didn't expect error here! got %v", err)
+				continue
+			}
+			if res.Output != expect {
+				t.Errorf(fsExpGot, expect, res.Output)
+			}
+		}
+	}
+}
+
+// ه with hamza above isn't composed by NFC, and is read as ۀ
+func TestEzafeRoundTrip(t *testing.T) {
+	for _, system := range []System{EI, Finglish} {
+		tlit := NewTranslitSystem(system)
+		for _, input := range []string{"خانهٔ من", "خانۀ من"} {
+			if err := tr.VerifyRoundTrip(tlit, input); err != nil {
+				t.Errorf("didn't expect error here! got %v", err)
+			}
+		}
+	}
+	// EI writes the he of خانه‌ی (or خانهِ) as h, the ezafe isn't a second he
+	ei := NewTranslitSystem(EI)
+	for _, input := range []string{"خانه\u200Cی من", "خانه\u0650 من"} {
+		res, err := ei.Transliterate(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != "ḵ’nh–ye mn" {
+			t.Errorf(fsExpGot, "ḵ’nh–ye mn", res.Output)
+		}
+		rev, err := ei.Reverse(res.Output)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		} else if rev.Output != "خانه\u200Cی من" {
+			t.Errorf(fsExpGot, "خانه\u200Cی من", rev.Output)
+		}
+	}
+	for input, expect := range map[string]string{"ḵ’n–ye mn": "خانۀ من", "dry’–ye mn": "دریای من"} {
+		rev, err := ei.Reverse(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		} else if rev.Output != expect {
+			t.Errorf(fsExpGot, expect, rev.Output)
+		}
+	}
+
+	res, err := NewTranslitSystem(Finglish).Reverse("khane^e mn")
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	} else if res.Output != "خانۀ من" {
+		t.Errorf(fsExpGot, "خانۀ من", res.Output)
+	}
+}
+
+func TestMarkEzafe(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		words  []int
+		expect string
+	}{
+		{"کتاب من", []int{0}, "کتابِ من"},
+		{"کتاب  خوب من", []int{0, 1}, "کتابِ  خوبِ من"},
+		{"کتابِ من", []int{0}, "کتابِ من"},
+		{"خانۀ من", []int{0}, "خانۀ من"},
+		{"خانهٔ من", []int{0}, "خانهٔ من"},
+		{"شهر، تهران", []int{0}, "شهرِ، تهران"},
+		{"محمّد", []int{0}, "محمّدِ"},
+	} {
+		if got := MarkEzafe(test.input, test.words...); got != test.expect {
+			t.Errorf(fsExpGot, test.expect, got)
+		}
+	}
+}
+
+// rules added with Extend are used in reverse conversion too
+func TestExtendReverse(t *testing.T) {
+	ext := tr.MustParseTable("ext.tsv", "ڤ\tvv\nگ\tgg\n")
+	for system, expect := range map[System]string{EI: "vvygg", Finglish: "vvigg"} {
+		tlit, err := NewTranslitSystem(system).Extend(ext)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		res, err := tlit.Transliterate("ڤیگ")
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+		if err := tr.VerifyRoundTrip(tlit.(tr.Reverser), "ڤیگ"); err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		}
+	}
+}
@@ -1,104 +1,82 @@
 package grc
 
 import (
-	"fmt"
+	_ "embed"
 	"regexp"
 	"strings"
+	"unicode"
 
 	tr "github.com/stts-se/translit"
 )
 
-type pair struct {
-	s1 string
-	s2 string
-}
-
-type repair struct {
-	from *regexp.Regexp
-	to   string
-}
-
 // https://en.wikipedia.org/wiki/Romanization_of_Greek#Modern_Greek
 // Simplified version of ALA-LC [3]
 
-var mapRegexps = []repair{
-	{from: regexp.MustCompile(`(^|[\s/()'".!?-])Γ[Κκ](.+)`), to: "${1}G${2}"},
-	{from: regexp.MustCompile(`(^|[\s/()'".!?-])Μ[Ππ](.+)`), to: "${1}B${2}"},
-	{from: regexp.MustCompile(`(^|[\s/()'".!?-])Ν[Ττ](.+)`), to: "${1}D${2}"},
-	{from: regexp.MustCompile(`(^|[\s/()'".!?-])(?i)γκ(.+)`), to: "${1}g${2}"},
-	{from: regexp.MustCompile(`(^|[\s/()'".!?-])(?i)μπ(.+)`), to: "${1}b${2}"},
-	{from: regexp.MustCompile(`(^|[\s/()'".!?-])(?i)ντ(.+)`), to: "${1}d${2}"},
-}
-
-var maptable = []pair{
-	{s1: "αι", s2: "ai"},
-	{s1: "ει", s2: "ei"},
-	{s1: "οι", s2: "oi"},
-	{s1: "υι", s2: "yi"},
-
-	{s1: "αυ", s2: "au"},
-	{s1: "ευ", s2: "eu"},
-	{s1: "ου", s2: "ou"},
-
-	{s1: "αύ", s2: "au"},
-	{s1: "εύ", s2: "eú"},
-	{s1: "ού", s2: "oú"},
-	{s1: "άυ", s2: "áu"},
-	{s1: "έυ", s2: "éu"},
-	{s1: "όυ", s2: "óu"},
-
-	{s1: "ήυ", s2: "íy"},
-	{s1: "υί", s2: "yí"},
-	{s1: "ηυ", s2: "iy"},
-
-	{s1: "ωυ", s2: "oy"},
-	{s1: "ώυ", s2: "óy"},
-
-	{s1: "μμπ", s2: "mb"},
-	{s1: "νντ", s2: "nd"},
-
-	{s1: "ά", s2: "á"},
-	{s1: "έ", s2: "é"},
-	{s1: "ή", s2: "í"},
-	{s1: "ί", s2: "í"},
-	{s1: "ύ", s2: "í"},
-	{s1: "ό", s2: "ó"},
-	{s1: "ώ", s2: "ó"},
-	{s1: "ϊ", s2: "ï"},
-	{s1: "ΐ", s2: "ḯ"},
-	{s1: "ϋ", s2: "ü"},
-	{s1: "ΰ", s2: "ǘ"},
-
-	{s1: "α", s2: "a"},
-	{s1: "β", s2: "v"},
-	{s1: "γγ", s2: "ng"},
-	{s1: "γκ", s2: "nk"},
-	{s1: "γξ", s2: "nx"},
-	{s1: "γχ", s2: "nch"},
-	{s1: "γ", s2: "g"},
-	{s1: "δ", s2: "d"},
-	{s1: "ε", s2: "e"},
-	{s1: "ζ", s2: "z"},
-
-	{s1: "η", s2: "i"},
-	{s1: "θ", s2: "th"},
-	{s1: "ι", s2: "i"},
-	{s1: "κ", s2: "k"},
-	{s1: "λ", s2: "l"},
-	{s1: "μ", s2: "m"},
-	{s1: "ν", s2: "n"},
-	{s1: "ξ", s2: "x"},
-	{s1: "ο", s2: "o"},
-	{s1: "π", s2: "p"},
-	{s1: "ρ", s2: "r"},
-	{s1: "ς", s2: "s"},
-	{s1: "σ", s2: "s"},
-	{s1: "τ", s2: "t"},
-	{s1: "υ", s2: "y"},
-	{s1: "φ", s2: "f"},
-	{s1: "χ", s2: "ch"},
-	{s1: "ψ", s2: "ps"},
-	{s1: "ω", s2: "o"},
+//go:embed tables/alalc.tsv
+var maptableData string
+
+var maptable = tr.MustParseTable("alalc.tsv", maptableData)
+
+//go:embed tables/alalc-rev.tsv
+var revTableData string
+
+// Latin to Greek, with ranked alternatives
+var revTable = tr.MustParseTable("alalc-rev.tsv", revTableData)
+
+//go:embed tables/elot743-t1.tsv
+var elot743T1Data string
+
+//go:embed tables/elot743-t2.tsv
+var elot743T2Data string
+
+// ELOT 743 / ISO 843 type 1 (transliteration)
+var elot743T1 = tr.MustParseTable("elot743-t1.tsv", elot743T1Data)
+
+// ELOT 743 / ISO 843 type 2 (transcription)
+var elot743T2 = tr.MustParseTable("elot743-t2.tsv", elot743T2Data)
+
+//go:embed tables/betacode.tsv
+var betaCodeData string
+
+//go:embed tables/classical.tsv
+var classicalData string
+
+// https://en.wikipedia.org/wiki/Beta_Code
+var betaCode = tr.MustParseTable("betacode.tsv", betaCodeData)
+
+// https://en.wikipedia.org/wiki/Romanization_of_Greek#Ancient_Greek
+var classical = tr.MustParseTable("classical.tsv", classicalData)
+
+// System is a romanisation system
+type System int
+
+const (
+	// ALALC is a simplified version of ALA-LC (default)
+	ALALC System = iota
+	// ELOT743Transliteration is ELOT 743 / ISO 843 type 1, reversible
+	ELOT743Transliteration
+	// ELOT743Transcription is ELOT 743 / ISO 843 type 2, as used on road signs and passports
+	ELOT743Transcription
+	// BetaCode is Beta Code (TLG), for polytonic Greek, reversible
+	BetaCode
+	// Classical is a scholarly romanisation of Ancient (polytonic) Greek
+	Classical
+)
+
+var systemNames = tr.SystemNames{"alalc", "elot743-t1", "elot743-t2", "betacode", "classical"}
+
+func (s System) String() string {
+	return systemNames.Name(int(s))
+}
+
+// Set implements the flag.Value interface, for the system names alalc, elot743-t1, elot743-t2, betacode and classical
+func (s *System) Set(name string) error {
+	i, err := systemNames.Index(name)
+	if err != nil {
+		return err
+	}
+	*s = System(i)
+	return nil
 }
 
 var commonCharsRE = regexp.MustCompile("[A-Za-z0-9()@΄$ï*_]")
@@ -119,65 +97,260 @@ var commonChars = map[string]bool{
 	"'":  true,
 }
 
-func Convert(s string) (string, error) {
-	s = tr.NFC(s)
-	for _, re := range mapRegexps {
-		s = re.from.ReplaceAllString(s, re.to)
+// Beta Code uses Latin letters and most ASCII punctuation for Greek letters, diacritics and signs, so only digits and the punctuation shared with Greek are accepted as they are
+func isBetaCommonChar(r rune) bool {
+	return strings.ContainsRune(" \t,.;?!–-", r) || unicode.IsDigit(r)
+}
+
+// Translit is a transliterator for one of the romanisation systems (see NewTranslitSystem)
+type Translit = tr.SystemTranslit
+
+// NewTranslit creates a transliterator for the default system (ALALC)
+func NewTranslit() Translit {
+	return systems[ALALC]
+}
+
+// NewTranslitSystem creates a transliterator for a romanisation system (ALALC for unknown values)
+func NewTranslitSystem(system System) Translit {
+	if system < 0 || int(system) >= len(systems) {
+		return systems[ALALC]
+	}
+	return systems[system]
+}
+
+var alalcScheme = tr.Scheme{
+	ID:          "el-Latn-x-alalc",
+	Source:      "el-Grek",
+	Target:      "el-Latn",
+	Description: "Greek to Latin script, simplified version of ALA-LC",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Romanization_of_Greek#Modern_Greek",
+	},
+}
+
+var elot743T1Scheme = tr.Scheme{
+	ID:          "el-Latn-x-elot743-t1",
+	Source:      "el-Grek",
+	Target:      "el-Latn",
+	Description: "Greek to Latin script, ELOT 743 / ISO 843 type 1 (transliteration), reversible",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Romanization_of_Greek#Modern_Greek",
+	},
+}
+
+var elot743T2Scheme = tr.Scheme{
+	ID:          "el-Latn-x-elot743-t2",
+	Source:      "el-Grek",
+	Target:      "el-Latn",
+	Description: "Greek to Latin script, ELOT 743 / ISO 843 type 2 (transcription)",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Romanization_of_Greek#Modern_Greek",
+	},
+}
+
+var betaCodeScheme = tr.Scheme{
+	ID:          "grc-Latn-x-betacode",
+	Source:      "grc-Grek",
+	Target:      "grc-Latn",
+	Description: "Ancient (polytonic) Greek to Beta Code, reversible",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Beta_Code",
+	},
+}
+
+var classicalScheme = tr.Scheme{
+	ID:          "grc-Latn-x-classical",
+	Source:      "grc-Grek",
+	Target:      "grc-Latn",
+	Description: "Ancient (polytonic) Greek to Latin script, scholarly romanisation",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Romanization_of_Greek#Ancient_Greek",
+	},
+}
+
+func init() {
+	for i, s := range systems {
+		system := System(i)
+		tr.Register(s.Scheme(), func() tr.Transliterator { return NewTranslitSystem(system) })
 	}
+}
 
-	intAll := []pair{}
-	for _, p := range maptable {
-		intAll = append(intAll, p)
-		for _, case1 := range tr.UpcaseInitials(p.s1) {
-			for _, case2 := range tr.UpcaseInitials(p.s2) {
-				intAll = append(intAll, pair{s1: case1, s2: case2})
+func isCommonChar(r rune) bool {
+	return commonChars[string(r)] || commonCharsRE.MatchString(string(r))
+}
+
+func buildRules(table tr.Table) []tr.Rule {
+	res := []tr.Rule{}
+	for _, r := range table.Rules {
+		res = append(res, r)
+		for _, case1 := range tr.UpcaseInitials(r.From) {
+			for _, case2 := range tr.UpcaseInitials(r.To) {
+				upcased := r
+				upcased.From, upcased.To = case1, case2
+				res = append(res, upcased)
 			}
 		}
-		// intAll = append(intAll, pair{s1: tr.UpcaseInitial(p.s1), s2: tr.UpcaseInitial(p.s2)})
-		// intAll = append(intAll, pair{s1: tr.Upcase(p.s1), s2: tr.Upcase(p.s2)})
-		// if len([]rune(p.s1)) > 2 {
-		// 	intAll = append(intAll, pair{s1: tr.UpcaseTwoInitials(p.s1), s2: tr.UpcaseInitial(p.s2)})
-		// }
+		upcased := r
+		upcased.From, upcased.To = tr.Upcase(r.From), tr.Upcase(r.To)
+		res = append(res, upcased)
 	}
-	res, err := innerConvert(intAll, s, true)
-	if err != nil {
-		return "", err
+	return res
+}
+
+// buildRevRules adds upper case versions of the reverse rules. Single letter rules are only added in title case, so that an upper case I is ranked as Ι, Η, Ει, etc, rather than as a mix of title and upper case alternatives.
+func buildRevRules(table tr.Table) []tr.Rule {
+	res := []tr.Rule{}
+	for _, r := range table.Rules {
+		res = append(res, r)
+		upcased := r
+		upcased.From, upcased.To = tr.UpcaseInitial(r.From), tr.UpcaseInitial(r.To)
+		res = append(res, upcased)
+		if len([]rune(r.From)) > 1 {
+			upcased.From, upcased.To = tr.Upcase(r.From), tr.Upcase(r.To)
+			res = append(res, upcased)
+		}
 	}
-	return res, nil
-}
-
-func innerConvert(chsAll []pair, s string, requireAllMapped bool) (string, error) {
-	sOrig := s
-	res := []string{}
-	for len(s) > 0 {
-		sStart := s
-		head := string([]rune(s)[0])
-		for _, p := range chsAll {
-			if strings.HasPrefix(s, p.s1) {
-				res = append(res, p.s2)
-				s = strings.TrimPrefix(s, p.s1)
-				break
+	return res
+}
+
+// buildBetaRules adds upper case letters (with an asterisk), and rules for each precomposed polytonic letter, built from the letter and the diacritic rules of the table. Beta Code writes the diacritics of upper case letters between the asterisk and the letter.
+func buildBetaRules(table tr.Table) []tr.Rule {
+	res := []tr.Rule{}
+	letters := map[rune]string{}
+	marks := map[rune]string{}
+	for _, r := range table.Rules {
+		res = append(res, r)
+		rs := []rune(r.From)
+		if len(rs) != 1 || !r.Context.IsEmpty() {
+			continue
+		}
+		switch {
+		case unicode.Is(unicode.Mn, rs[0]):
+			marks[rs[0]] = r.To
+		case unicode.IsLower(rs[0]):
+			letters[rs[0]] = r.To
+			// σ and ς share the upper case Σ
+			if up := unicode.ToUpper(rs[0]); up != rs[0] && !unicode.IsUpper(rs[0]) && rs[0] != 'ς' {
+				res = append(res, tr.Rule{From: string(up), To: "*" + r.To})
 			}
-			// check for common chars
-			if _, ok := commonChars[head]; ok {
-				res = append(res, head)
-				s = strings.TrimPrefix(s, head)
-				break
+		}
+	}
+	for _, block := range [][]rune{{0x0370, 0x03FF}, {0x1F00, 0x1FFF}} {
+		for c := block[0]; c <= block[1]; c++ {
+			if !unicode.IsLetter(c) || tr.NFC(string(c)) != string(c) {
+				continue
 			}
-			if commonCharsRE.MatchString(head) {
-				res = append(res, head)
-				s = strings.TrimPrefix(s, head)
-				break
+			rs := []rune(tr.NFD(string(c)))
+			letter, ok := letters[unicode.ToLower(rs[0])]
+			if len(rs) < 2 || !ok {
+				continue
 			}
-		}
-		if s == sStart { // nothing found to map for this prefix
-			if requireAllMapped {
-				return "", fmt.Errorf("Couldn't convert '%s' in '%s'", s, sOrig)
-			} else if s == sStart {
-				res = append(res, head)
-				s = strings.TrimPrefix(s, head)
+			diacritics := ""
+			for _, m := range rs[1:] {
+				if marks[m] == "" {
+					ok = false
+				}
+				diacritics += marks[m]
 			}
+			if !ok {
+				continue
+			}
+			if unicode.IsLower(rs[0]) {
+				res = append(res, tr.Rule{From: string(c), To: letter + diacritics})
+			} else {
+				res = append(res, tr.Rule{From: string(c), To: "*" + diacritics + letter})
+			}
+		}
+	}
+	return res
+}
+
+// buildBetaRevRules swaps the Beta Code rules, accepting both lower case (Perseus) and upper case (TLG) Beta Code
+func buildBetaRevRules(rules []tr.Rule) []tr.Rule {
+	res := []tr.Rule{}
+	for _, r := range swapSigmaRules(rules) {
+		res = append(res, r)
+		if up := tr.Upcase(r.From); up != r.From {
+			upcased := r
+			upcased.From = up
+			res = append(res, upcased)
+		}
+	}
+	return res
+}
+
+// Latin letters are not common characters in reverse conversion
+func isRevCommonChar(r rune) bool {
+	return commonChars[string(r)] || unicode.IsDigit(r)
+}
+
+// newSystem creates a transliterator for a system with upper case rules added by buildRules
+func newSystem(scheme tr.Scheme, table tr.Table) Translit {
+	return tr.NewSystemTranslit(tr.SystemConfig{Scheme: scheme, Table: table, Rules: buildRules, IsCommonChar: isCommonChar})
+}
+
+// newCandidateSystem creates a transliterator for modern Greek, with ranked candidates for reverse conversion. Both the simplified ALA-LC and the ELOT 743 type 2 spellings (e.g. av, ef for αυ, ευ) are accepted, accents in the input are kept, and σ is written ς at the end of words.
+func newCandidateSystem(scheme tr.Scheme, table tr.Table) Translit {
+	return tr.NewSystemTranslit(tr.SystemConfig{Scheme: scheme, Table: table, Rules: buildRules, IsCommonChar: isCommonChar,
+		RevTable: &revTable, RevTableRules: buildRevRules, RevIsCommonChar: isRevCommonChar, RevPost: finalSigma})
+}
+
+// newDecomposedSystem creates a transliterator for decomposed (NFD) input, so that the diacritics of polytonic letters can be mapped one by one
+func newDecomposedSystem(scheme tr.Scheme, table tr.Table) Translit {
+	return tr.NewSystemTranslit(tr.SystemConfig{Scheme: scheme, Table: table, Rules: buildRules, IsCommonChar: isCommonChar, Decomposed: true})
+}
+
+// newReversibleSystem creates a transliterator with one-to-one reverse conversion, using the rev function to build the reverse rules from the conversion rules
+func newReversibleSystem(scheme tr.Scheme, table tr.Table, rev func([]tr.Rule) []tr.Rule) Translit {
+	return tr.NewSystemTranslit(tr.SystemConfig{Scheme: scheme, Table: table, Rules: buildRules, IsCommonChar: isCommonChar,
+		RevRules: rev, RevIsCommonChar: isRevCommonChar})
+}
+
+// newBetaCodeSystem creates the Beta Code transliterator, where ASCII punctuation is only accepted as it is if it can be converted back (see isBetaCommonChar)
+func newBetaCodeSystem() Translit {
+	return tr.NewSystemTranslit(tr.SystemConfig{Scheme: betaCodeScheme, Table: betaCode, Rules: buildBetaRules, IsCommonChar: isBetaCommonChar,
+		RevRules: buildBetaRevRules, RevIsCommonChar: isBetaCommonChar})
+}
+
+// swapRules swaps the source and target of each rule (the first rule wins for targets shared by several rules)
+func swapRules(rules []tr.Rule) []tr.Rule {
+	res := []tr.Rule{}
+	for _, r := range rules {
+		res = append(res, tr.Rule{From: tr.NFC(r.To), To: r.From})
+	}
+	return res
+}
+
+// finalSigmaRule is the reverse rule for s at the end of a word
+var finalSigmaRule = tr.Rule{From: "s", To: "ς", Comment: "final sigma", Context: tr.MustParseContext("_#", nil)}
+
+// swapSigmaRules swaps the rules (see swapRules), converting s into ς at the end of a word
+func swapSigmaRules(rules []tr.Rule) []tr.Rule {
+	return append([]tr.Rule{finalSigmaRule}, swapRules(rules)...)
+}
+
+// systems holds the transliterator of each System
+var systems = []Translit{
+	ALALC:                  newCandidateSystem(alalcScheme, maptable),
+	ELOT743Transliteration: newReversibleSystem(elot743T1Scheme, elot743T1, swapSigmaRules),
+	ELOT743Transcription:   newCandidateSystem(elot743T2Scheme, elot743T2),
+	BetaCode:               newBetaCodeSystem(),
+	Classical:              newDecomposedSystem(classicalScheme, classical),
+}
+
+func Convert(s string) (string, error) {
+	res, err := NewTranslit().Transliterate(s)
+	return res.Output, err
+}
+
+// finalSigma replaces σ with ς at the end of a word, in the output of reverse conversion. Both letters have the same length in UTF-8, so the segment offsets are unchanged.
+func finalSigma(res tr.Result) tr.Result {
+	rs := []rune(res.Output)
+	for i, r := range rs {
+		if r == 'σ' && (i+1 == len(rs) || !unicode.IsLetter(rs[i+1]) && !unicode.Is(unicode.Mn, rs[i+1])) {
+			rs[i] = 'ς'
 		}
 	}
-	return strings.Join(res, ""), nil
+	res.Output = string(rs)
+	return res
 }
@@ -0,0 +1,254 @@
+package grc
+
+import (
+	"errors"
+	"testing"
+
+	tr "github.com/stts-se/translit"
+)
+
+var fsExpGot = "Expected: %v got: %v"
+
+func TestReverse(t *testing.T) {
+	tl := NewTranslit()
+	for input, expect := range map[string]string{
+		"Thessaloníki":   "Θεσσαλονίκη",
+		"Vólos":          "Βόλος",
+		"Kavála":         "Καβάλα",
+		"Náfpaktos":      "Ναύπακτος",
+		"Efthymíou":      "Ευθυμίου",
+		"Aígina, Pátra":  "Αίγινα, Πάτρα",
+		"Mpoúmpoulina":   "Μπούμπουλινα",
+		"Agios Nikolaos": "Αγιος Νικολαος",
+	} {
+		res, err := tl.Reverse(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+	}
+}
+
+// ev before a vowel is ευ word initially, and mostly εβ within words
+func TestReverseDiphthongs(t *testing.T) {
+	for _, system := range []System{ALALC, ELOT743Transcription} {
+		tl := NewTranslitSystem(system)
+		for input, expect := range map[string]string{
+			"Evángelos": "Ευάγγελος",
+			"EVANTHÍA":  "ΕΥΑΝΘΊΑ",
+			"Avgí":      "Αυγή",
+			"Lávrio":    "Λαύριο",
+			"Préveza":   "Πρέβεζα",
+			"Kavála":    "Καβάλα",
+			"Náfpaktos": "Ναύπακτος",
+		} {
+			res, err := tl.Reverse(input)
+			if err != nil {
+				t.Errorf("didn't expect error here! got %v", err)
+				continue
+			}
+			if res.Output != expect {
+				t.Errorf(fsExpGot, expect, res.Output)
+			}
+		}
+	}
+}
+
+func TestRevert(t *testing.T) {
+	tl := NewTranslit()
+	for input, expect := range map[string]string{
+		"Athína": "Αθήνα",
+		"Kríti":  "Κρήτη",
+		"Chíos":  "Χίος",
+	} {
+		res, err := tl.Revert(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		found := false
+		for _, s := range res {
+			if s == expect {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf(fsExpGot, expect, res)
+		}
+	}
+}
+
+func TestELOT743(t *testing.T) {
+	for system, tests := range map[System]map[string]string{
+		ELOT743Transliteration: {
+			"Θεσσαλονίκη":  "Thessaloníkī",
+			"Ναύπακτος":    "Naýpaktos",
+			"Ευάγγελος":    "Eyággelos",
+			"Μπουμπουλίνα": "Mpoumpoulína",
+			"Ωρωπός":       "Ōrōpós",
+			"προϋπόθεση":   "proÿpóthesī",
+		},
+		ELOT743Transcription: {
+			"Θεσσαλονίκη":  "Thessaloníki",
+			"Ναύπακτος":    "Náfpaktos",
+			"Ευάγγελος":    "Evángelos",
+			"Μπουμπουλίνα": "Boumpoulína",
+			"Ντόρα":        "Dóra",
+			"Αγκίστρι":     "Agkístri",
+			"Ωρωπός":       "Oropós",
+			"Λευκάδα":      "Lefkáda",
+		},
+	} {
+		tlit := NewTranslitSystem(system)
+		for input, expect := range tests {
+			res, err := tlit.Transliterate(input)
+			if err != nil {
+				t.Errorf("didn't expect error here! got %v", err)
+				continue
+			}
+			if res.Output != expect {
+				t.Errorf(fsExpGot, expect, res.Output)
+			}
+			if system != ELOT743Transliteration {
+				continue
+			}
+			if err := tr.VerifyRoundTrip(tlit, input); err != nil {
+				t.Errorf("didn't expect error here! got %v", err)
+			}
+		}
+	}
+}
+
+func TestSystemLookup(t *testing.T) {
+	for _, id := range []string{"el-Latn-x-alalc", "el-Latn-x-elot743-t1", "el-Latn-x-elot743-t2", "grc-Latn-x-betacode", "grc-Latn-x-classical"} {
+		tlit, err := tr.Lookup(id)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if got := tlit.Scheme().ID; got != id {
+			t.Errorf(fsExpGot, id, got)
+		}
+	}
+}
+
+func TestBetaCode(t *testing.T) {
+	tlit := NewTranslitSystem(BetaCode)
+	for input, expect := range map[string]string{
+		"μῆνιν ἄειδε θεὰ":    "mh=nin a)/eide qea\\",
+		"Ἀχιλῆος· οὐλομένην": "*)axilh=os: ou)lome/nhn",
+		"ᾌδης ῥήτωρ":         "*)/|adhs r(h/twr",
+		"ᾠδή, ΑΘΗΝΑΙ":        "w)|dh/, *a*q*h*n*a*i",
+	} {
+		res, err := tlit.Transliterate(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+		if err := tr.VerifyRoundTrip(tlit, input); err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		}
+		// decomposed input
+		res, err = tlit.Transliterate(tr.NFD(input))
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		} else if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+	}
+
+	// upper case (TLG) Beta Code
+	res, err := tlit.Reverse("MH=NIN A)/EIDE *PHLHI+A/DEW")
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	} else if expect := "μῆνιν ἄειδε Πηληϊάδεω"; res.Output != expect {
+		t.Errorf(fsExpGot, expect, res.Output)
+	}
+
+	// explicit medial (s1) and final (s2) sigma, where s would be read the other way round
+	for input, expect := range map[string]string{
+		"λόγος λόγοσ":   "lo/gos lo/gos1",
+		"λόγος2 λόγοσ2": "lo/gos22 lo/gos12",
+		"ςα, σ3 ϲ":      "s2a, s13 s3",
+		"δ’ ἄλγεα·":     "d' a)/lgea:",
+	} {
+		res, err := tlit.Transliterate(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+		if err := tr.VerifyRoundTrip(tlit, input); err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		}
+		if err := tr.VerifyReverseRoundTrip(tlit, expect); err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		}
+	}
+
+	// ASCII punctuation used in Beta Code can't be converted back
+	for _, input := range []string{"δ' ἄλγεα", "ἄλγεα:", "λόγος/"} {
+		_, err := tlit.Transliterate(input)
+		var e *tr.Error
+		if !errors.As(err, &e) {
+			t.Errorf(fsExpGot, "*translit.Error", err)
+		}
+	}
+}
+
+func TestClassical(t *testing.T) {
+	tlit := NewTranslitSystem(Classical)
+	for input, expect := range map[string]string{
+		"μῆνιν ἄειδε θεὰ":        "mênin áeide theà",
+		"Ἡρόδοτος Ἁλικαρνησσέος": "Hēródotos Halikarnēsséos",
+		"οἱ αὐτοῦ υἱός":          "hoi autoû huiós",
+		"ῥήτωρ Πύρρος":           "rhḗtōr Pýrrhos",
+		"ᾠδή, ἧς":                "ōidḗ, hês",
+		"ἄνθρωπος":               "ánthrōpos",
+	} {
+		for _, in := range []string{input, tr.NFD(input)} {
+			res, err := tlit.Transliterate(in)
+			if err != nil {
+				t.Errorf("didn't expect error here! got %v", err)
+				continue
+			}
+			if res.Output != expect {
+				t.Errorf(fsExpGot, expect, res.Output)
+			}
+			if last := res.Segments[len(res.Segments)-1]; last.OutputEnd != len(res.Output) {
+				t.Errorf(fsExpGot, len(res.Output), last.OutputEnd)
+			}
+		}
+	}
+	if tlit.Reversible() {
+		t.Errorf(fsExpGot, false, true)
+	}
+}
+
+// rules added with Extend are used in reverse conversion too
+func TestExtendReverse(t *testing.T) {
+	ext := tr.MustParseTable("ext.tsv", "ϳ\tj\n")
+	for _, system := range []System{ALALC, ELOT743Transliteration, ELOT743Transcription, BetaCode} {
+		tlit, err := NewTranslitSystem(system).Extend(ext)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		res, err := tlit.(tr.Reverser).Reverse("jas")
+		if err != nil {
+			t.Errorf("%v: didn't expect error here! got %v", system, err)
+			continue
+		}
+		if res.Output != "ϳας" {
+			t.Errorf("%v: "+fsExpGot, system, "ϳας", res.Output)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Package cli contains the input/output loop shared by the command line tools
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tr "github.com/stts-se/translit"
+)
+
+const stdinName = "<stdin>"
+
+// Processor runs a transliterator over input strings, files or stdin, and prints the result
+type Processor struct {
+	Transliterator tr.Transliterator
+	Reverse        bool             // Reverse conversion (target to source script)
+	Candidates     int              // Max number of candidates to print for reverse conversion (see translit.CandidateReverser)
+	EchoInput      bool             // Print input and result, separated by tab
+	FailOnError    bool             // Exit on first conversion error
+	PrintSource    bool             // Prefix each output line with the input file basename (or <stdin>)
+	Stats          bool             // Print processing statistics to stderr when done
+	TableFile      string           // Mapping table file with rules to add to the built-in table (see translit.Extensible)
+	Unknown        tr.UnknownPolicy // Policy for unknown input characters (see translit.UnknownHandler)
+
+	Out    io.Writer
+	ErrOut io.Writer
+
+	nIn, nPrinted, nSkipped int
+}
+
+// NewProcessor creates a processor for the transliterator, writing to stdout and stderr
+func NewProcessor(t tr.Transliterator) *Processor {
+	return &Processor{Transliterator: t, Out: os.Stdout, ErrOut: os.Stderr}
+}
+
+// Flags registers the standard processing flags (-e, -f, -table, -unknown, and -r if reversible is true) on the flag set
+func (p *Processor) Flags(fs *flag.FlagSet, reversible bool) {
+	fs.BoolVar(&p.EchoInput, "e", p.EchoInput, "Echo input (default: false)")
+	fs.BoolVar(&p.FailOnError, "f", p.FailOnError, "Fail on error (default: false)")
+	if reversible {
+		fs.BoolVar(&p.Reverse, "r", p.Reverse, "Reverse conversion (Latin to source script)")
+		fs.IntVar(&p.Candidates, "n", p.Candidates, "Print up to `n` tab separated candidates for reverse conversion, most likely first (for schemes with ranked candidates)")
+	}
+	fs.StringVar(&p.TableFile, "table", p.TableFile, "Mapping table `file` with rules to add to (or override) the built-in table")
+	fs.Var(&p.Unknown, "unknown", "Unknown input character `policy`: fail, replace[:<string>], pass, drop or escape (default: scheme default)")
+}
+
+// Extend adds the rules of the mapping table file to the transliterator's built-in table
+func Extend(t tr.Transliterator, tableFile string) (tr.Transliterator, error) {
+	ext, ok := t.(tr.Extensible)
+	if !ok {
+		return t, fmt.Errorf("scheme %s doesn't support user defined mapping tables", t.Scheme())
+	}
+	table, err := tr.ReadTable(tableFile)
+	if err != nil {
+		return t, err
+	}
+	return ext.Extend(table)
+}
+
+func (p *Processor) convert(s string) (tr.Result, error) {
+	if p.Reverse {
+		rev, ok := p.Transliterator.(tr.Reverser)
+		if !ok {
+			return tr.Result{}, fmt.Errorf("scheme %s is not reversible", p.Transliterator.Scheme())
+		}
+		if cr, ok := rev.(tr.CandidateReverser); ok && p.Candidates > 1 {
+			cands, err := cr.ReverseCandidates(s, p.Candidates)
+			if err != nil {
+				return tr.Result{}, err
+			}
+			outputs := []string{}
+			for _, c := range cands {
+				outputs = append(outputs, c.Output)
+			}
+			return tr.Result{Input: s, Output: strings.Join(outputs, "\t")}, nil
+		}
+		return rev.Reverse(s)
+	}
+	return p.Transliterator.Transliterate(s)
+}
+
+// Process converts a single input string, and prints the result. The source is the name of the input file (used with PrintSource).
+func (p *Processor) Process(source, s string) error {
+	p.nIn++
+	res, err := p.convert(s)
+	if err != nil {
+		if p.FailOnError {
+			return err
+		}
+		fmt.Fprintf(p.ErrOut, "ERROR %s\t%v\n", s, err)
+		p.nSkipped++
+		return nil
+	}
+	if p.PrintSource {
+		fmt.Fprintf(p.Out, "%s\t", source)
+	}
+	if p.EchoInput {
+		fmt.Fprintf(p.Out, "%s\t%s\n", s, res.Output)
+	} else {
+		fmt.Fprintf(p.Out, "%s\n", res.Output)
+	}
+	p.nPrinted++
+	return nil
+}
+
+func (p *Processor) processReader(source string, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if err := p.Process(source, scanner.Text()); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read '%s' : %v", source, err)
+	}
+	return nil
+}
+
+// Run processes the arguments, each of which is either an input file or an input string. If there are no arguments, input is read from stdin.
+func (p *Processor) Run(args []string) error {
+	if p.TableFile != "" {
+		t, err := Extend(p.Transliterator, p.TableFile)
+		if err != nil {
+			return err
+		}
+		p.Transliterator = t
+		p.TableFile = ""
+	}
+	if p.Unknown.Action != tr.UnknownDefault {
+		t, err := tr.SetUnknownPolicy(p.Transliterator, p.Unknown)
+		if err != nil {
+			return err
+		}
+		p.Transliterator = t
+	}
+	if p.Reverse && !tr.IsReversible(p.Transliterator) {
+		return fmt.Errorf("scheme %s is not reversible", p.Transliterator.Scheme())
+	}
+	if len(args) == 0 {
+		if err := p.processReader(stdinName, os.Stdin); err != nil {
+			return err
+		}
+	}
+	for _, arg := range args {
+		if tr.IsFile(arg) {
+			r, err := tr.OpenFile(arg)
+			if err != nil {
+				return fmt.Errorf("couldn't read file: %v", err)
+			}
+			err = p.processReader(filepath.Base(arg), r)
+			r.Close()
+			if err != nil {
+				return err
+			}
+		} else if err := p.Process(stdinName, arg); err != nil {
+			return err
+		}
+	}
+	if p.Stats {
+		p.printStats()
+	}
+	return nil
+}
+
+func (p *Processor) printStats() {
+	pluralS := "s"
+	if p.nIn == 1 {
+		pluralS = ""
+	}
+	fmt.Fprintf(p.ErrOut, "PROCESSED % 7d utterance%s\n", p.nIn, pluralS)
+	fmt.Fprintf(p.ErrOut, "  SKIPPED % 7d\n", p.nSkipped)
+	fmt.Fprintf(p.ErrOut, "  PRINTED % 7d\n", p.nPrinted)
+}
+
+// Usage returns a usage function for a command line tool, printing the description, the standard usage lines and the flag defaults
+func Usage(fs *flag.FlagSet, cmdname, description string) func() {
+	return func() {
+		out := fs.Output()
+		fmt.Fprintln(out, description)
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Usage:")
+		fmt.Fprintln(out, cmdname+" <input file(s)>")
+		fmt.Fprintln(out, cmdname+" <input string(s)>")
+		fmt.Fprintln(out, "cat <input file(s)> | "+cmdname)
+		fmt.Fprintln(out, "\nOptional flags:")
+		fs.PrintDefaults()
+	}
+}
+
+// ParseFlags parses the command line flags of a single-scheme command line tool, adding a -h flag and a usage message with the description
+func ParseFlags(description string) {
+	cmdname := filepath.Base(os.Args[0])
+	help := flag.Bool("h", false, "Print help and exit")
+	flag.Usage = func() {
+		Usage(flag.CommandLine, cmdname, description)()
+		os.Exit(0)
+	}
+	flag.Parse()
+	if *help {
+		flag.Usage()
+	}
+}
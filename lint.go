@@ -0,0 +1,232 @@
+package translit
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// LintKind is the kind of problem found by LintTable
+type LintKind int
+
+const (
+	// LintDuplicate is a rule with the same source string and context as an earlier rule
+	LintDuplicate LintKind = iota
+	// LintUnreachable is a rule that can never be applied, since its source string isn't normalised like the input, or an earlier rule with the same source string applies wherever its context matches
+	LintUnreachable
+	// LintAmbiguous is a rule with a target string that can't be mapped back unambiguously
+	LintAmbiguous
+	// LintUncovered is a letter of the source language (or script) not covered by any rule
+	LintUncovered
+)
+
+var lintKindNames = []string{"duplicate", "unreachable", "ambiguous", "uncovered"}
+
+func (k LintKind) String() string {
+	if k < 0 || int(k) >= len(lintKindNames) {
+		return fmt.Sprintf("LintKind(%d)", int(k))
+	}
+	return lintKindNames[k]
+}
+
+// LintIssue is a problem found in a mapping table
+type LintIssue struct {
+	Kind    LintKind
+	Rule    Rule // The rule concerned (zero for LintUncovered)
+	Char    rune // The uncovered character (LintUncovered only)
+	Message string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s\t%s", i.Kind, i.Message)
+}
+
+// LintOptions holds the settings for LintTable
+type LintOptions struct {
+	Reverse    bool   // Check that the rules can be used for reverse conversion (target to source)
+	Alphabet   string // Letters of the source language, e.g. Alphabet("ru"), for coverage checks. Leave empty to skip.
+	Script     string // ISO 15924 code of the source script, e.g. Cyrl (see ScriptCode), for coverage checks of every letter in the main Unicode block of the script (if Alphabet is empty). Leave empty to skip.
+	Decomposed bool   // The input is decomposed (NFD) before conversion, rather than composed (NFC)
+}
+
+// scriptBlocks maps ISO 15924 script codes to the Unicode script, and the range of its main Unicode block
+var scriptBlocks = map[string]struct {
+	script string
+	lo, hi rune
+}{
+	"Arab": {"Arabic", 0x0600, 0x06FF},
+	"Cyrl": {"Cyrillic", 0x0400, 0x04FF},
+	"Grek": {"Greek", 0x0370, 0x03FF},
+	"Taml": {"Tamil", 0x0B80, 0x0BFF},
+}
+
+// alphabets holds the letters (and combining marks) of the source languages of the built-in schemes, in lower case
+var alphabets = map[string]string{
+	"ar":  "ءآأؤإئابةتثجحخدذرزسشصضطظعغفقكلمنهوىي\u064B\u064C\u064D\u064E\u064F\u0650\u0651\u0652",
+	"be":  "абвгдеёжзійклмнопрстуўфхцчшыьэюя",
+	"bg":  "абвгдежзийклмнопрстуфхцчшщъьюя",
+	"el":  "αάβγδεέζηήθιίϊΐκλμνξοόπρσςτυύϋΰφχψωώ",
+	"fa":  "ءآابپتثجچحخدذرزژسشصضطظعغفقکگلمنوهیۀ",
+	"grc": "αβγδεζηθικλμνξοπρσςτυφχψω\u0300\u0301\u0308\u0313\u0314\u0342\u0345",
+	"mk":  "абвгдѓежзѕијклљмнњопрстќуфхцчџш",
+	"ru":  "абвгдеёжзийклмнопрстуфхцчшщъыьэюя",
+	"sr":  "абвгдђежзијклљмнњопрстћуфхцчџш",
+	"ta":  "அஆஇஈஉஊஎஏஐஒஓஔஃகஙசஞடணதநபமயரலவழளறனஜஶஷஸஹ\u0BBE\u0BBF\u0BC0\u0BC1\u0BC2\u0BC6\u0BC7\u0BC8\u0BCA\u0BCB\u0BCC\u0BCD",
+	"uk":  "абвгґдеєжзиіїйклмнопрстуфхцчшщьюя",
+}
+
+// Alphabet returns the letters (and combining marks) of the language of a language tag (e.g. ru for ru-Cyrl), in lower case, or an empty string for unknown languages
+func Alphabet(tag string) string {
+	return alphabets[strings.Split(tag, "-")[0]]
+}
+
+// ScriptCode returns the script subtag of a language tag (e.g. Cyrl for ru-Cyrl), or an empty string
+func ScriptCode(tag string) string {
+	for _, sub := range strings.Split(tag, "-") {
+		if len(sub) == 4 && unicode.IsUpper(rune(sub[0])) {
+			return sub
+		}
+	}
+	return ""
+}
+
+// LintTable checks a mapping table for duplicate rules, rules that can never be applied (source strings that aren't normalised like the input, or contextual rules shadowed by an earlier rule), target strings that can't be mapped back unambiguously (with opts.Reverse), and letters of the source language or script that aren't covered by any rule (with opts.Alphabet or opts.Script). Issues are returned in rule order.
+func LintTable(t Table, opts LintOptions) []LintIssue {
+	res := []LintIssue{}
+	form, formName := norm.NFC, "NFC"
+	if opts.Decomposed {
+		form, formName = norm.NFD, "NFD"
+	}
+	seen := map[string]Rule{}
+	contextual := map[string][]Rule{} // source string -> earlier rules with context
+	for _, r := range t.Rules {
+		if prev, ok := seen[r.key()]; ok {
+			msg := fmt.Sprintf("%s: duplicate rule, ignored", ruleString(r))
+			if prev.To != r.To {
+				msg = fmt.Sprintf("%s: duplicate rule, ignored (using '%s')", ruleString(r), prev.To)
+			}
+			res = append(res, LintIssue{Kind: LintDuplicate, Rule: r, Message: msg})
+			continue
+		}
+		seen[r.key()] = r
+		if !form.IsNormalString(r.From) {
+			res = append(res, LintIssue{Kind: LintUnreachable, Rule: r, Message: fmt.Sprintf("%s: source string is not %s normalised (input is)", ruleString(r), formName)})
+			continue
+		}
+		if r.Context.IsEmpty() {
+			continue
+		}
+		for _, prev := range contextual[r.From] {
+			if prev.Context.covers(r.Context) {
+				res = append(res, LintIssue{Kind: LintUnreachable, Rule: r, Message: fmt.Sprintf("%s: shadowed by earlier rule %s", ruleString(r), ruleString(prev))})
+				break
+			}
+		}
+		contextual[r.From] = append(contextual[r.From], r)
+	}
+	if opts.Reverse {
+		res = append(res, lintReverse(t)...)
+	}
+	if opts.Alphabet != "" {
+		res = append(res, lintCoverage(t, []rune(opts.Alphabet))...)
+	} else if opts.Script != "" {
+		res = append(res, lintCoverage(t, scriptLetters(opts.Script))...)
+	}
+	return res
+}
+
+func ruleString(r Rule) string {
+	res := fmt.Sprintf("'%s' -> '%s'", escape(r.From), escape(r.To))
+	if !r.Context.IsEmpty() {
+		res = fmt.Sprintf("%s / %s", res, r.Context)
+	}
+	return res
+}
+
+func lintReverse(t Table) []LintIssue {
+	res := []LintIssue{}
+	sources := map[string][]string{} // target -> source strings
+	for _, r := range t.Rules {
+		if !StringsContains(sources[r.To], r.From) {
+			sources[r.To] = append(sources[r.To], r.From)
+		}
+	}
+	reported := map[string]bool{}
+	for _, r := range t.Rules {
+		if reported[r.To] {
+			continue
+		}
+		reported[r.To] = true
+		if r.To == "" {
+			res = append(res, LintIssue{Kind: LintAmbiguous, Rule: r, Message: fmt.Sprintf("%s: empty target, can't be mapped back", ruleString(r))})
+			continue
+		}
+		if len(sources[r.To]) > 1 {
+			res = append(res, LintIssue{Kind: LintAmbiguous, Rule: r, Message: fmt.Sprintf("%s: target is shared by several source strings: %s", ruleString(r), strings.Join(sources[r.To], ", "))})
+			continue
+		}
+		if parts := splitTarget([]rune(r.To), sources); len(parts) > 1 {
+			froms := []string{}
+			for _, p := range parts {
+				froms = append(froms, sources[p][0])
+			}
+			res = append(res, LintIssue{Kind: LintAmbiguous, Rule: r, Message: fmt.Sprintf("%s: target can also be read as '%s' (from '%s')", ruleString(r), strings.Join(parts, "' + '"), strings.Join(froms, "' + '"))})
+		}
+	}
+	return res
+}
+
+// splitTarget returns a split of the target into two or more other targets, or nil
+func splitTarget(target []rune, sources map[string][]string) []string {
+	// splits[i] holds a split of target[:i], if any
+	splits := make([][]string, len(target)+1)
+	splits[0] = []string{}
+	for i := 1; i <= len(target); i++ {
+		for j := 0; j < i; j++ {
+			if splits[j] == nil || (j == 0 && i == len(target)) {
+				continue
+			}
+			part := string(target[j:i])
+			if _, ok := sources[part]; ok {
+				splits[i] = append(append([]string{}, splits[j]...), part)
+				break
+			}
+		}
+	}
+	return splits[len(target)]
+}
+
+// scriptLetters returns the letters and combining marks of the script in the main Unicode block of the script
+func scriptLetters(script string) []rune {
+	block, ok := scriptBlocks[script]
+	if !ok {
+		return nil
+	}
+	res := []rune{}
+	for c := block.lo; c <= block.hi; c++ {
+		if unicode.Is(unicode.Scripts[block.script], c) && (unicode.IsLetter(c) || unicode.IsMark(c)) {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+func lintCoverage(t Table, letters []rune) []LintIssue {
+	covered := map[rune]bool{}
+	for _, r := range t.Rules {
+		for _, c := range r.From {
+			covered[c] = true
+		}
+	}
+	res := []LintIssue{}
+	for _, c := range letters {
+		if covered[c] || covered[unicode.ToLower(c)] {
+			continue
+		}
+		info := UnicodeInfo(string(c))[0]
+		res = append(res, LintIssue{Kind: LintUncovered, Char: c, Message: fmt.Sprintf("%s %s %s: not covered by any rule", info.Char, info.Code, info.Name)})
+	}
+	return res
+}
@@ -0,0 +1,133 @@
+package translit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintTable(t *testing.T) {
+	data := `а	a
+б	b
+с	s
+х	h
+ш	sh
+ч	ch
+ц	ts
+щ	shch
+т	t
+ъ	
+э	e
+е	e
+е	ye	duplicate
+е	e	duplicate, same target
+е	ye	word initial	#_
+е\u0308	ё	not NFC
+`
+	table, err := ParseTable("test", strings.NewReader(data))
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+		return
+	}
+	issues := LintTable(table, LintOptions{Reverse: true, Script: "Cyrl"})
+	count := map[LintKind]int{}
+	messages := []string{}
+	for _, i := range issues {
+		count[i.Kind]++
+		if i.Kind != LintUncovered {
+			messages = append(messages, i.String())
+		}
+	}
+	expect := []string{
+		"duplicate\t'е' -> 'ye': duplicate rule, ignored (using 'e')",
+		"duplicate\t'е' -> 'e': duplicate rule, ignored",
+		"unreachable\t'е\\u0308' -> 'ё': source string is not NFC normalised (input is)",
+		"ambiguous\t'ш' -> 'sh': target can also be read as 's' + 'h' (from 'с' + 'х')",
+		"ambiguous\t'ц' -> 'ts': target can also be read as 't' + 's' (from 'т' + 'с')",
+		"ambiguous\t'щ' -> 'shch': target can also be read as 'sh' + 'ch' (from 'ш' + 'ч')",
+		"ambiguous\t'ъ' -> '': empty target, can't be mapped back",
+		"ambiguous\t'э' -> 'e': target is shared by several source strings: э, е",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expect, "\n") {
+		t.Errorf(fsExpGot, strings.Join(expect, "\n"), strings.Join(messages, "\n"))
+	}
+	// upper case versions are covered by lower case rules
+	if count[LintUncovered] == 0 {
+		t.Errorf("expected uncovered characters")
+	}
+	for _, i := range issues {
+		if i.Kind == LintUncovered && (i.Char == 'б' || i.Char == 'Б') {
+			t.Errorf("didn't expect %c to be uncovered", i.Char)
+		}
+	}
+
+	if issues := LintTable(table, LintOptions{}); len(issues) != 3 {
+		t.Errorf(fsExpGot, 3, len(issues))
+	}
+}
+
+func TestLintShadowed(t *testing.T) {
+	data := `#class vowel aeiou
+#class front ei
+c	s	before front vowels	_{front}
+c	s	before e, shadowed	_e
+c	s	before e at word end, shadowed	_e#
+c	k	before vowels, not shadowed	_{vowel}
+c	ch	after s, not shadowed	s_
+c	k	except before h	_[^h]
+c	k	before a, shadowed	_a
+c	k	before h, not shadowed	_h
+c	k
+`
+	table, err := ParseTable("test", strings.NewReader(data))
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+		return
+	}
+	messages := []string{}
+	for _, i := range LintTable(table, LintOptions{}) {
+		messages = append(messages, i.String())
+	}
+	expect := []string{
+		"unreachable\t'c' -> 's' / _e: shadowed by earlier rule 'c' -> 's' / _{front}",
+		"unreachable\t'c' -> 's' / _e#: shadowed by earlier rule 'c' -> 's' / _{front}",
+		"unreachable\t'c' -> 'k' / _a: shadowed by earlier rule 'c' -> 'k' / _{vowel}",
+	}
+	if strings.Join(messages, "\n") != strings.Join(expect, "\n") {
+		t.Errorf(fsExpGot, strings.Join(expect, "\n"), strings.Join(messages, "\n"))
+	}
+}
+
+func TestLintAlphabet(t *testing.T) {
+	data := `а	a
+б	b
+е\u0308	ё
+`
+	table, err := ParseTable("test", strings.NewReader(data))
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+		return
+	}
+	issues := LintTable(table, LintOptions{Alphabet: Alphabet("ru-Cyrl"), Decomposed: true})
+	if len(issues) != 30 {
+		t.Errorf(fsExpGot, 30, len(issues))
+	}
+	for _, i := range issues {
+		if i.Kind != LintUncovered {
+			t.Errorf(fsExpGot, LintUncovered, i)
+		}
+		if i.Char == 'б' || i.Char == 'ј' {
+			t.Errorf("didn't expect %c to be uncovered", i.Char)
+		}
+	}
+	if res := Alphabet("xx-Latn"); res != "" {
+		t.Errorf(fsExpGot, "", res)
+	}
+}
+
+func TestScriptCode(t *testing.T) {
+	for tag, expect := range map[string]string{"ru-Cyrl": "Cyrl", "sr-Cyrl-RS": "Cyrl", "el": "", "ta-Latn-x-iso15919": "Latn"} {
+		if res := ScriptCode(tag); res != expect {
+			t.Errorf(fsExpGot, expect, res)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package mkd
+
+import (
+	_ "embed"
+
+	tr "github.com/stts-se/translit"
+)
+
+// https://en.wikipedia.org/wiki/Romanization_of_Macedonian
+// Macedonian Latin alphabet, one-to-one with the Cyrillic alphabet
+
+//go:embed tables/latinica.tsv
+var maptableData string
+
+//go:embed tables/latinica-rev.tsv
+var revTableData string
+
+var maptable = tr.MustParseTable("latinica.tsv", maptableData)
+
+// Latin to Cyrillic rules, added to the reversed maptable. Word stems where a digraph (dz, dž, lj, nj) represents two Cyrillic letters, such as podzemen, are matched at the start of a word.
+var revTable = tr.MustParseTable("latinica-rev.tsv", revTableData)
+
+var latinicaScheme = tr.Scheme{
+	ID:          "mk-Latn-x-latinica",
+	Source:      "mk-Cyrl",
+	Target:      "mk-Latn",
+	Description: "Macedonian Cyrillic to Latin script (Macedonian Latin alphabet), and reverse",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Romanization_of_Macedonian",
+	},
+}
+
+// Digraphs for upper case letters are written in title case (Lj for Љ, Dz for Ѕ), or in upper case (LJ) next to other upper case letters
+var translit = tr.NewTableTranslit(tr.TableConfig{
+	Scheme:         latinicaScheme,
+	Table:          maptable,
+	RevTable:       &revTable,
+	CommonChars:    "'„“”",
+	UpcaseDigraphs: true,
+})
+
+// Translit converts Macedonian Cyrillic to Latin script, and reverse
+type Translit = tr.TableTranslit
+
+// NewTranslit returns a transliterator between Macedonian Cyrillic and Latin script
+func NewTranslit() Translit {
+	return translit
+}
+
+func init() {
+	tr.Register(latinicaScheme, func() tr.Transliterator { return NewTranslit() })
+}
@@ -0,0 +1,49 @@
+package mkd
+
+import (
+	"testing"
+
+	tr "github.com/stts-se/translit"
+)
+
+var fsExpGot = "Expected: %v got: %v"
+
+var tests = map[string]string{
+	"Скопје Охрид Битола":       "Skopje Ohrid Bitola",
+	"Ѓорѓи Ќерка Ѕвезда":        "Ǵorǵi Ḱerka Dzvezda",
+	"ЅВЕЗДА ЉУБОВ ЏЕБ":          "DZVEZDA LJUBOV DŽEB",
+	"Љубов, Њива, Џеб":          "Ljubov, Njiva, Džeb",
+	"подземен надзор инјекција": "podzemen nadzor injekcija",
+	"ПОДЗЕМЕН Надзор":           "PODZEMEN Nadzor",
+}
+
+func TestTransliterate(t *testing.T) {
+	tlit := NewTranslit()
+	for input, expect := range tests {
+		res, err := tlit.Transliterate(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	tlit := NewTranslit()
+	for expect, input := range tests {
+		res, err := tlit.Reverse(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+		if err := tr.VerifyRoundTrip(tlit, expect); err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package translit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Factory creates a new Transliterator for a registered scheme
+type Factory func() Transliterator
+
+type registryEntry struct {
+	scheme  Scheme
+	factory Factory
+}
+
+var registry = struct {
+	sync.RWMutex
+	entries map[string]registryEntry
+}{entries: map[string]registryEntry{}}
+
+// Register makes a scheme available by its ID. It is typically called from the init function of the package implementing the scheme.
+// Register panics if the scheme ID is empty, or if a scheme with the same ID is already registered.
+func Register(scheme Scheme, factory Factory) {
+	if scheme.ID == "" {
+		panic("translit: Register called with empty scheme ID")
+	}
+	if factory == nil {
+		panic(fmt.Sprintf("translit: Register called with nil factory for scheme '%s'", scheme.ID))
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if _, exists := registry.entries[scheme.ID]; exists {
+		panic(fmt.Sprintf("translit: Register called twice for scheme '%s'", scheme.ID))
+	}
+	registry.entries[scheme.ID] = registryEntry{scheme: scheme, factory: factory}
+}
+
+// Lookup creates a new Transliterator for the scheme with the given ID.
+// Only schemes in packages that have been imported (possibly as blank imports) are available.
+func Lookup(id string) (Transliterator, error) {
+	registry.RLock()
+	entry, ok := registry.entries[id]
+	registry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown scheme '%s'", id)
+	}
+	return entry.factory(), nil
+}
+
+// List returns all registered schemes, sorted by ID
+func List() []Scheme {
+	registry.RLock()
+	defer registry.RUnlock()
+	res := []Scheme{}
+	for _, e := range registry.entries {
+		res = append(res, e.scheme)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res
+}
+
+// Find returns the registered schemes with a source tag matching the input tag, sorted by ID.
+// The input tag matches if it is equal to the source tag, or a prefix of it (e.g. ru or ru-Cyrl both match ru-Cyrl).
+func Find(source string) []Scheme {
+	res := []Scheme{}
+	for _, s := range List() {
+		if tagMatches(source, s.Source) {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+func tagMatches(prefix, tag string) bool {
+	prefix = strings.ToLower(prefix)
+	tag = strings.ToLower(tag)
+	return tag == prefix || strings.HasPrefix(tag, prefix+"-")
+}
@@ -0,0 +1,58 @@
+package translit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type upcaser struct{ scheme Scheme }
+
+func (u upcaser) Scheme() Scheme { return u.scheme }
+func (u upcaser) Transliterate(s string) (Result, error) {
+	return Result{Input: s, Output: strings.ToUpper(s)}, nil
+}
+
+func TestRegistry(t *testing.T) {
+	s1 := Scheme{ID: "xx-Latn-x-test1", Source: "xx-Latn", Target: "xx-Latn"}
+	s2 := Scheme{ID: "xx-Latn-x-test2", Source: "xy-Latn", Target: "xx-Latn"}
+	Register(s2, func() Transliterator { return upcaser{s2} })
+	Register(s1, func() Transliterator { return upcaser{s1} })
+
+	tl, err := Lookup("xx-Latn-x-test1")
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+		return
+	}
+	if tl.Scheme().ID != s1.ID {
+		t.Errorf(fsExpGot, s1.ID, tl.Scheme().ID)
+	}
+	res, _ := tl.Transliterate("abc")
+	if res.Output != "ABC" {
+		t.Errorf(fsExpGot, "ABC", res.Output)
+	}
+
+	if _, err := Lookup("xx-Latn-x-undefined"); err == nil {
+		t.Errorf("expected error here!")
+	}
+
+	var ids []string
+	for _, s := range List() {
+		if strings.HasPrefix(s.ID, "xx-") {
+			ids = append(ids, s.ID)
+		}
+	}
+	if expect := []string{s1.ID, s2.ID}; !reflect.DeepEqual(ids, expect) {
+		t.Errorf(fsExpGot, expect, ids)
+	}
+
+	for _, tag := range []string{"xy", "XY-Latn"} {
+		found := Find(tag)
+		if len(found) != 1 || found[0].ID != s2.ID {
+			t.Errorf(fsExpGot, []Scheme{s2}, found)
+		}
+	}
+	if found := Find("x"); len(found) != 0 {
+		t.Errorf(fsExpGot, []Scheme{}, found)
+	}
+}
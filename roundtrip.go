@@ -0,0 +1,97 @@
+package translit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RoundTripError is returned when an input string converted and then converted back doesn't match the original input
+type RoundTripError struct {
+	Scheme   string   // Scheme ID, if known
+	Input    string   // Input string
+	Output   string   // Converted string
+	Reversed string   // Output converted back
+	Offset   int      // Rune offset of the first difference between Input and Reversed
+	Segment  *Segment // The segment of the first conversion where the round trip diverges, if known
+}
+
+// NewRoundTripError creates an error for an input string, converted into output, and converted back into reversed. The input and reversed strings are expected to differ.
+func NewRoundTripError(input, output, reversed string) *RoundTripError {
+	in, rev := []rune(input), []rune(reversed)
+	i := 0
+	for i < len(in) && i < len(rev) && in[i] == rev[i] {
+		i++
+	}
+	return &RoundTripError{Input: input, Output: output, Reversed: reversed, Offset: i}
+}
+
+func (e *RoundTripError) Error() string {
+	msg := fmt.Sprintf("reverse test failed: input '%s', mapped '%s', remapped '%s'", e.Input, e.Output, e.Reversed)
+	if e.Segment != nil {
+		msg = fmt.Sprintf("%s, diverging at '%s' -> '%s'", msg, e.Input[e.Segment.InputStart:e.Segment.InputEnd], e.Output[e.Segment.OutputStart:e.Segment.OutputEnd])
+	} else {
+		msg = fmt.Sprintf("%s, diverging at offset %d", msg, e.Offset)
+	}
+	if e.Scheme != "" {
+		msg = fmt.Sprintf("%s: %s", e.Scheme, msg)
+	}
+	return msg
+}
+
+func roundTrip(scheme Scheme, forward, backward func(string) (Result, error), input string) error {
+	res, err := forward(input)
+	if err != nil {
+		return err
+	}
+	rev, err := backward(res.Output)
+	if err != nil {
+		return err
+	}
+	if rev.Output == res.Input {
+		return nil
+	}
+	e := NewRoundTripError(res.Input, res.Output, rev.Output)
+	e.Scheme = scheme.ID
+	e.Segment = res.SegmentAt(e.Offset)
+	return e
+}
+
+// VerifyRoundTrip converts the input string from the source script into the target script, and back again. If the result doesn't match the (normalised) input, a *RoundTripError is returned. Conversion errors are returned as they are.
+func VerifyRoundTrip(r Reverser, input string) error {
+	return roundTrip(r.Scheme(), r.Transliterate, r.Reverse, input)
+}
+
+// VerifyReverseRoundTrip is like VerifyRoundTrip, but for input in the target script, converted into the source script and back again
+func VerifyReverseRoundTrip(r Reverser, input string) error {
+	return roundTrip(r.Scheme(), r.Reverse, r.Transliterate, input)
+}
+
+// CorpusFailure is an input line that failed verification (see VerifyCorpus)
+type CorpusFailure struct {
+	Line  int    // Line number, starting at 1
+	Input string // Input line
+	Err   error  // Verification error, typically a *RoundTripError or an *Error
+}
+
+// VerifyCorpus runs the verify function (e.g. VerifyRoundTrip for a scheme) on each non-empty line of the input, and returns the failed lines and the number of lines verified
+func VerifyCorpus(in io.Reader, verify func(input string) error) ([]CorpusFailure, int, error) {
+	res := []CorpusFailure{}
+	n := 0
+	scanner := bufio.NewScanner(in)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n++
+		if err := verify(line); err != nil {
+			res = append(res, CorpusFailure{Line: lineNo, Input: line, Err: err})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return res, n, fmt.Errorf("failed to read input : %v", err)
+	}
+	return res, n, nil
+}
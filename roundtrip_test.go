@@ -0,0 +1,57 @@
+package translit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVerifyRoundTrip(t *testing.T) {
+	if err := VerifyRoundTrip(digraphs{}, "ша щ"); err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if err := VerifyReverseRoundTrip(digraphs{}, "sha shch"); err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+
+	err := VerifyRoundTrip(digraphs{}, "ша сх")
+	var e *RoundTripError
+	if !errors.As(err, &e) {
+		t.Errorf(fsExpGot, "*RoundTripError", err)
+		return
+	}
+	if e.Scheme != "xx-digraphs" || e.Offset != 3 || e.Reversed != "ша щ" {
+		t.Errorf(fsExpGot, "xx-digraphs 3 'ша щ'", e)
+	}
+	if e.Segment == nil || e.Input[e.Segment.InputStart:e.Segment.InputEnd] != "сх" {
+		t.Errorf(fsExpGot, "segment сх", e.Segment)
+	}
+	if !strings.HasSuffix(e.Error(), "diverging at 'сх' -> 'shch'") {
+		t.Errorf(fsExpGot, "diverging at 'сх' -> 'shch'", e.Error())
+	}
+
+	// conversion errors are returned as is
+	var e2 *Error
+	if err := VerifyRoundTrip(digraphs{}, "шча"); !errors.As(err, &e2) {
+		t.Errorf(fsExpGot, "*Error", err)
+	}
+
+	e = NewRoundTripError("abc", "x", "ab")
+	if e.Offset != 2 || e.Segment != nil {
+		t.Errorf(fsExpGot, 2, e.Offset)
+	}
+}
+
+func TestVerifyCorpus(t *testing.T) {
+	corpus := "ша\n\nсх ша\nщ\nшча\n"
+	failures, n, err := VerifyCorpus(strings.NewReader(corpus), func(s string) error { return VerifyRoundTrip(digraphs{}, s) })
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if n != 4 {
+		t.Errorf(fsExpGot, 4, n)
+	}
+	if len(failures) != 2 || failures[0].Line != 3 || failures[0].Input != "сх ша" || failures[1].Line != 5 {
+		t.Errorf(fsExpGot, "lines 3 and 5", failures)
+	}
+}
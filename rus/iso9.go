@@ -0,0 +1,155 @@
+package rus
+
+import (
+	_ "embed"
+	"fmt"
+
+	tr "github.com/stts-se/translit"
+)
+
+// Strict is a transliterator for one-to-one schemes (ISO 9 and GOST 7.79 System B), for archival use. Each conversion is verified by converting the result back, and a *translit.RoundTripError is returned if the original input can't be recovered.
+type Strict struct {
+	scheme       tr.Scheme
+	table        tr.Table
+	converter    tr.Converter
+	revConverter tr.Converter
+	unknown      tr.UnknownPolicy
+}
+
+//go:embed tables/iso9.tsv
+var iso9Data string
+
+//go:embed tables/gost779b.tsv
+var gost779BData string
+
+// https://en.wikipedia.org/wiki/ISO_9
+var iso9 = tr.MustParseTable("iso9.tsv", iso9Data)
+
+// https://en.wikipedia.org/wiki/GOST_7.79-2000
+var gost779B = tr.MustParseTable("gost779b.tsv", gost779BData)
+
+var iso9Scheme = tr.Scheme{
+	ID:          "ru-Latn-x-iso9",
+	Source:      "ru-Cyrl",
+	Target:      "ru-Latn",
+	Description: "Russian to Latin script, ISO 9:1995 (GOST 7.79 System A), reversible",
+	References: []string{
+		"https://en.wikipedia.org/wiki/ISO_9",
+	},
+}
+
+var gost779BScheme = tr.Scheme{
+	ID:          "ru-Latn-x-gost779b",
+	Source:      "ru-Cyrl",
+	Target:      "ru-Latn",
+	Description: "Russian to Latin script, GOST 7.79-2000 System B (ASCII), reversible",
+	References: []string{
+		"https://en.wikipedia.org/wiki/GOST_7.79-2000",
+	},
+}
+
+func init() {
+	tr.Register(iso9Scheme, func() tr.Transliterator { return NewISO9() })
+	tr.Register(gost779BScheme, func() tr.Transliterator { return NewGOST779B() })
+}
+
+var _ tr.Reverser = Strict{}
+var _ tr.Extensible = Strict{}
+var _ tr.UnknownHandler = Strict{}
+
+// NewISO9 creates a transliterator for ISO 9:1995, using a single (possibly accented) Latin character for each Cyrillic character
+func NewISO9() Strict {
+	return newStrict(iso9Scheme, iso9)
+}
+
+// NewGOST779B creates a transliterator for GOST 7.79-2000 System B, using ASCII characters only
+func NewGOST779B() Strict {
+	return newStrict(gost779BScheme, gost779B)
+}
+
+// strictSystems maps the names of the one-to-one schemes to their constructors
+var strictSystems = map[string]func() Strict{
+	"iso9":  NewISO9,
+	"gostb": NewGOST779B,
+}
+
+// NewStrictSystem creates a transliterator for a one-to-one scheme, by name: iso9 or gostb
+func NewStrictSystem(name string) (Strict, error) {
+	if f, ok := strictSystems[name]; ok {
+		return f(), nil
+	}
+	return Strict{}, fmt.Errorf("invalid system '%s' (expected iso9 or gostb)", name)
+}
+
+func newStrict(scheme tr.Scheme, table tr.Table) Strict {
+	rules := buildRules(table)
+	revRules := []tr.Rule{}
+	for _, r := range rules {
+		revRules = append(revRules, tr.Rule{From: tr.NFC(r.To), To: r.From})
+	}
+	return Strict{
+		scheme:       scheme,
+		table:        table,
+		converter:    tr.NewConverter(rules, isCommonChar),
+		revConverter: tr.NewConverter(revRules, isCommonChar),
+	}
+}
+
+// Scheme returns the scheme metadata
+func (s Strict) Scheme() tr.Scheme {
+	return s.scheme
+}
+
+// Table returns the mapping table for the Cyrillic input
+func (s Strict) Table() tr.Table {
+	return s.table
+}
+
+// Extend returns a copy of the transliterator, with the rules of the input table added to the Cyrillic mapping table. Rules are expected in lower case, upper case versions are added automatically. The reverse mapping is updated accordingly.
+func (s Strict) Extend(t tr.Table) (tr.Transliterator, error) {
+	res := newStrict(s.scheme, s.table.Merge(t))
+	res.unknown = s.unknown
+	return res, nil
+}
+
+// WithUnknownPolicy returns a copy of the transliterator, using the policy for unknown input characters. The round trip check is skipped for policies that don't fail on unknown characters, since the original input can't be recovered.
+func (s Strict) WithUnknownPolicy(p tr.UnknownPolicy) tr.Transliterator {
+	s.unknown = p
+	return s
+}
+
+func (s Strict) checkRoundTrip() bool {
+	return s.unknown.Action == tr.UnknownDefault || s.unknown.Action == tr.UnknownFail
+}
+
+func (s Strict) convert(c, revC tr.Converter, input string) (tr.Result, error) {
+	input = tr.NFC(input)
+	output, segs, err := c.WithUnknownPolicy(s.unknown).ConvertSegments(input, true)
+	if err != nil {
+		return tr.Result{Input: input}, tr.WithScheme(err, s.scheme)
+	}
+	res := matchCase(tr.Result{Input: input, Output: output, Segments: segs})
+	if !s.checkRoundTrip() {
+		return res, nil
+	}
+	// unmapped characters are copied, and will show up as a difference
+	reversedOutput, reversedSegs, _ := revC.ConvertSegments(res.Output, false)
+	reversed := matchCase(tr.Result{Input: res.Output, Output: reversedOutput, Segments: reversedSegs}).Output
+	if reversed != input {
+		e := tr.NewRoundTripError(input, res.Output, reversed)
+		e.Scheme = s.scheme.ID
+		e.Segment = res.SegmentAt(e.Offset)
+		return res, e
+	}
+	return res, nil
+}
+
+// Transliterate converts the input string from Cyrillic to Latin script. A *translit.RoundTripError is returned if the result can't be converted back into the input string.
+func (s Strict) Transliterate(input string) (tr.Result, error) {
+	return s.convert(s.converter, s.revConverter, input)
+}
+
+// Reverse converts the input string from Latin to Cyrillic script. A *translit.RoundTripError is returned if the result can't be converted back into the input string.
+func (s Strict) Reverse(input string) (tr.Result, error) {
+	return s.convert(s.revConverter, s.converter, input)
+}
@@ -0,0 +1,84 @@
+package rus
+
+import (
+	"errors"
+	"testing"
+
+	tr "github.com/stts-se/translit"
+)
+
+func TestStrict(t *testing.T) {
+	for _, test := range []struct {
+		tlit  Strict
+		tests map[string]string
+	}{
+		{NewISO9(), map[string]string{
+			"Щука":           "Ŝuka",
+			"ЩУКА":           "ŜUKA",
+			"объём":          "obʺëm",
+			"ОБЪЁМ":          "OBʺËM",
+			"Подъезд":        "Podʺezd",
+			"ПОДЪЕЗД":        "PODʺEZD",
+			"царь":           "carʹ",
+			"ЦАРЬ":           "CARʹ",
+			"МЫШЬ И ЦАРЬ":    "MYŠʹ I CARʹ",
+			"Въ":             "Vʺ",
+			"Юрий Гагарин":   "Ûrij Gagarin",
+			"ЮРИЙ ГАГАРИН":   "ÛRIJ GAGARIN",
+			"Съезд, съёмка.": "Sʺezd, sʺëmka.",
+		}},
+		{NewGOST779B(), map[string]string{
+			"Щука":           "Shhuka",
+			"ЩУКА":           "SHHUKA",
+			"БОРЩ":           "BORSHH",
+			"объём":          "ob``yom",
+			"ОБЪЁМ":          "OB``YOM",
+			"Подъезд":        "Pod``ezd",
+			"ПОДЪЕЗД":        "POD``EZD",
+			"Царь":           "Czar`",
+			"ЦАРЬ":           "CZAR`",
+			"ЦИРК":           "CIRK",
+			"МЫШЬ И ЦАРЬ":    "MY'SH` I CZAR`",
+			"Въ":             "V``",
+			"Юрий Гагарин":   "Yurij Gagarin",
+			"ЮРИЙ ГАГАРИН":   "YURIJ GAGARIN",
+			"ЭХО":            "E`XO",
+			"Съезд, съёмка.": "S``ezd, s``yomka.",
+		}},
+	} {
+		for input, expect := range test.tests {
+			res, err := test.tlit.Transliterate(input)
+			if err != nil {
+				t.Errorf("didn't expect error here! got %v", err)
+				continue
+			}
+			if res.Output != expect {
+				t.Errorf(fsExpGot, expect, res.Output)
+			}
+			rev, err := test.tlit.Reverse(expect)
+			if err != nil {
+				t.Errorf("didn't expect error here! got %v", err)
+				continue
+			}
+			if rev.Output != input {
+				t.Errorf(fsExpGot, input, rev.Output)
+			}
+		}
+	}
+}
+
+func TestStrictRoundTripError(t *testing.T) {
+	// mixed case digraphs can't be converted back into the same Latin spelling
+	_, err := NewGOST779B().Reverse("SHHuka")
+	var e *tr.RoundTripError
+	if !errors.As(err, &e) {
+		t.Errorf(fsExpGot, "*translit.RoundTripError", err)
+	}
+
+	// H is only used in digraphs
+	_, err = NewGOST779B().Reverse("ShHuka")
+	var ue *tr.Error
+	if !errors.As(err, &ue) {
+		t.Errorf(fsExpGot, "*translit.Error", err)
+	}
+}
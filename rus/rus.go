@@ -3,72 +3,286 @@ package rus
 // References:
 // https://en.wikipedia.org/wiki/Romanization_of_Russian
 // https://tt.se/tt-spraket/ord-och-begrepp/internationellt/andra-sprak/ryska/
+// https://en.wikipedia.org/wiki/ISO_9
+// https://en.wikipedia.org/wiki/GOST_7.79-2000
+// https://en.wikipedia.org/wiki/BGN/PCGN_romanization_of_Russian
+// https://en.wikipedia.org/wiki/ALA-LC_romanization_for_Russian
+// https://de.wikipedia.org/wiki/Deutsche_Transkription_aus_dem_Russischen
+// https://fr.wikipedia.org/wiki/Transcription_du_russe_en_français
+// https://fi.wikipedia.org/wiki/Venäjän_translitterointi
+// https://no.wikipedia.org/wiki/Translitterasjon_av_russisk
 
 import (
+	_ "embed"
 	"fmt"
-	"regexp"
+	"sort"
 	"strings"
 
 	tr "github.com/stts-se/translit"
 )
 
-type pair struct {
-	s1 string
-	s2 string
+// System is a romanisation system for the international (not language specific) output
+type System int
+
+const (
+	// RoadSigns is a simplified version of the 'Road signs' system (default)
+	RoadSigns System = iota
+	// BGNPCGN is the BGN/PCGN (1947) system
+	BGNPCGN
+	// ALALC is the ALA-LC (1997) system, with tie bars
+	ALALC
+	// ALALCNoTieBars is the ALA-LC (1997) system, without tie bars
+	ALALCNoTieBars
+	// ICAO is the ICAO Doc 9303 system for machine readable travel documents
+	ICAO
+)
+
+var systemNames = tr.SystemNames{"roadsigns", "bgnpcgn", "alalc", "alalc-notie", "icao"}
+
+func (s System) String() string {
+	return systemNames.Name(int(s))
 }
 
-type rPair struct {
-	from *regexp.Regexp
-	to   string
+// Set implements the flag.Value interface, for the system names roadsigns, bgnpcgn, alalc, alalc-notie and icao
+func (s *System) Set(name string) error {
+	i, err := systemNames.Index(name)
+	if err != nil {
+		return err
+	}
+	*s = System(i)
+	return nil
 }
 
-// Translit
+// Translit is a transliterator for a romanisation system (see NewTranslitSystem), or a language specific transcription (see NewTranslitLanguage)
 type Translit struct {
-	SwedishOutput bool
+	tr.SystemTranslit
 }
 
+// NewTranslit creates a transliterator for international (road signs) or Swedish output.
+//
+// Deprecated: use NewTranslitLanguage("sv") for Swedish output, and NewTranslitSystem for international output.
 func NewTranslit(swedishOutput bool) Translit {
-	return Translit{SwedishOutput: swedishOutput}
-}
-
-var roadSigns = []pair{ // https://en.wikipedia.org/wiki/Romanization_of_Russian -- Road signs
-	{s1: "а", s2: "a"},
-	{s1: "б", s2: "b"},
-	{s1: "в", s2: "v"},
-	{s1: "г", s2: "g"},
-	{s1: "д", s2: "d"},
-	{s1: "е", s2: "e"},
-	{s1: "ё", s2: "e"},
-	{s1: "ж", s2: "zh"},
-	{s1: "з", s2: "z"},
-	{s1: "и", s2: "i"},
-	{s1: "й", s2: "y"}, // j
-	{s1: "к", s2: "k"},
-	{s1: "л", s2: "l"},
-	{s1: "м", s2: "m"},
-	{s1: "н", s2: "n"},
-	{s1: "о", s2: "o"},
-	{s1: "п", s2: "p"},
-	{s1: "р", s2: "r"},
-	{s1: "с", s2: "s"},
-	{s1: "т", s2: "t"},
-	{s1: "у", s2: "u"},
-	{s1: "ф", s2: "f"},
-	{s1: "х", s2: "kh"},
-	{s1: "ц", s2: "ts"},
-	{s1: "ч", s2: "ch"},
-	{s1: "ш", s2: "sh"},
-	{s1: "щ", s2: "shch"},
-	{s1: "ъ", s2: "ie"}, // ’
-	{s1: "ы", s2: "y"},
-	{s1: "ь", s2: "’"},
-	{s1: "э", s2: "e"},
-	{s1: "ю", s2: "yu"}, // ju
-	{s1: "я", s2: "ya"}, // ja
+	if swedishOutput {
+		return Translit{languages["sv"]}
+	}
+	return NewTranslitSystem(RoadSigns)
+}
+
+// NewTranslitLanguage creates a transliterator for a language specific transcription (see Languages)
+func NewTranslitLanguage(lang string) (Translit, error) {
+	l, ok := languages[lang]
+	if !ok {
+		return Translit{}, fmt.Errorf("invalid target language '%s' (expected %s)", lang, strings.Join(Languages(), ", "))
+	}
+	return Translit{l}, nil
+}
+
+// Languages returns the target languages of the language specific transcriptions, sorted
+func Languages() []string {
+	res := []string{}
+	for lang := range languages {
+		res = append(res, lang)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// NewTranslitSystem creates a transliterator for a romanisation system (international output, RoadSigns for unknown values)
+func NewTranslitSystem(system System) Translit {
+	if system < 0 || int(system) >= len(systems) {
+		return Translit{systems[RoadSigns]}
+	}
+	return Translit{systems[system]}
+}
+
+var roadSignsScheme = tr.Scheme{
+	ID:          "ru-Latn-x-roadsigns",
+	Source:      "ru-Cyrl",
+	Target:      "ru-Latn",
+	Description: "Russian to Latin script, simplified 'Road signs' system",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Romanization_of_Russian",
+	},
+}
+
+var ttSweScheme = tr.Scheme{
+	ID:          "ru-Latn-x-tt-sv",
+	Source:      "ru-Cyrl",
+	Target:      "sv-Latn",
+	Description: "Russian to Latin script, Swedish style (TT recommendations)",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Romanization_of_Russian",
+		"https://tt.se/tt-spraket/ord-och-begrepp/internationellt/andra-sprak/ryska/",
+	},
+}
+
+var dudenDeScheme = tr.Scheme{
+	ID:          "ru-Latn-x-duden-de",
+	Source:      "ru-Cyrl",
+	Target:      "de-Latn",
+	Description: "Russian to Latin script, German style (Duden)",
+	References: []string{
+		"https://de.wikipedia.org/wiki/Deutsche_Transkription_aus_dem_Russischen",
+	},
+}
+
+var frScheme = tr.Scheme{
+	ID:          "ru-Latn-x-fr",
+	Source:      "ru-Cyrl",
+	Target:      "fr-Latn",
+	Description: "Russian to Latin script, French style",
+	References: []string{
+		"https://fr.wikipedia.org/wiki/Transcription_du_russe_en_français",
+	},
+}
+
+var fiScheme = tr.Scheme{
+	ID:          "ru-Latn-x-fi",
+	Source:      "ru-Cyrl",
+	Target:      "fi-Latn",
+	Description: "Russian to Latin script, Finnish style (Kotus recommendations)",
+	References: []string{
+		"https://fi.wikipedia.org/wiki/Venäjän_translitterointi",
+	},
+}
+
+var noScheme = tr.Scheme{
+	ID:          "ru-Latn-x-no",
+	Source:      "ru-Cyrl",
+	Target:      "no-Latn",
+	Description: "Russian to Latin script, Norwegian style (Språkrådet recommendations)",
+	References: []string{
+		"https://no.wikipedia.org/wiki/Translitterasjon_av_russisk",
+	},
+}
+
+var bgnPCGNScheme = tr.Scheme{
+	ID:          "ru-Latn-x-bgnpcgn",
+	Source:      "ru-Cyrl",
+	Target:      "ru-Latn",
+	Description: "Russian to Latin script, BGN/PCGN (1947)",
+	References: []string{
+		"https://en.wikipedia.org/wiki/BGN/PCGN_romanization_of_Russian",
+	},
+}
+
+var alaLCScheme = tr.Scheme{
+	ID:          "ru-Latn-x-alalc",
+	Source:      "ru-Cyrl",
+	Target:      "ru-Latn",
+	Description: "Russian to Latin script, ALA-LC (1997), with tie bars",
+	References: []string{
+		"https://en.wikipedia.org/wiki/ALA-LC_romanization_for_Russian",
+	},
 }
 
+var alaLCNoTieBarsScheme = tr.Scheme{
+	ID:          "ru-Latn-x-alalc-notie",
+	Source:      "ru-Cyrl",
+	Target:      "ru-Latn",
+	Description: "Russian to Latin script, ALA-LC (1997), without tie bars",
+	References: []string{
+		"https://en.wikipedia.org/wiki/ALA-LC_romanization_for_Russian",
+	},
+}
+
+var icaoScheme = tr.Scheme{
+	ID:          "ru-Latn-x-icao",
+	Source:      "ru-Cyrl",
+	Target:      "ru-Latn",
+	Description: "Russian to Latin script, ICAO Doc 9303 (machine readable passports)",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Romanization_of_Russian",
+	},
+}
+
+func init() {
+	for system := range systems {
+		system := System(system)
+		tr.Register(systems[system].Scheme(), func() tr.Transliterator { return NewTranslitSystem(system) })
+	}
+	for _, lang := range Languages() {
+		lang := lang
+		tr.Register(languages[lang].Scheme(), func() tr.Transliterator { return Translit{languages[lang]} })
+	}
+}
+
+//go:embed tables/roadsigns.tsv
+var roadSignsData string
+
+//go:embed tables/roadsigns-rev.tsv
+var roadSignsRevData string
+
+//go:embed tables/tt-sv.tsv
+var ttSweData string
+
+//go:embed tables/duden-de.tsv
+var dudenDeData string
+
+//go:embed tables/fr.tsv
+var frData string
+
+//go:embed tables/fi.tsv
+var fiData string
+
+//go:embed tables/no.tsv
+var noData string
+
+// https://en.wikipedia.org/wiki/Romanization_of_Russian -- Road signs
+var roadSigns = tr.MustParseTable("roadsigns.tsv", roadSignsData)
+
+// Reverse road signs, Latin to Cyrillic, with ranked alternatives
+var roadSignsRev = tr.MustParseTable("roadsigns-rev.tsv", roadSignsRevData)
+
+//go:embed tables/bgnpcgn.tsv
+var bgnPCGNData string
+
+//go:embed tables/alalc.tsv
+var alaLCData string
+
+//go:embed tables/icao.tsv
+var icaoData string
+
+// https://en.wikipedia.org/wiki/BGN/PCGN_romanization_of_Russian
+var bgnPCGN = tr.MustParseTable("bgnpcgn.tsv", bgnPCGNData)
+
+// https://en.wikipedia.org/wiki/ALA-LC_romanization_for_Russian
+var alaLC = tr.MustParseTable("alalc.tsv", alaLCData)
+var alaLCNoTieBars = removeTieBars(alaLC)
+
+// https://en.wikipedia.org/wiki/Romanization_of_Russian -- ICAO
+var icao = tr.MustParseTable("icao.tsv", icaoData)
+
+// https://tt.se/tt-spraket/ord-och-begrepp/internationellt/andra-sprak/ryska/
+var ttSwe = tr.MustParseTable("tt-sv.tsv", ttSweData)
+
+// https://de.wikipedia.org/wiki/Deutsche_Transkription_aus_dem_Russischen
+var german = tr.MustParseTable("duden-de.tsv", dudenDeData)
+
+// https://fr.wikipedia.org/wiki/Transcription_du_russe_en_français
+var french = tr.MustParseTable("fr.tsv", frData)
+
+// https://fi.wikipedia.org/wiki/Venäjän_translitterointi
+var finnish = tr.MustParseTable("fi.tsv", fiData)
+
+// https://no.wikipedia.org/wiki/Translitterasjon_av_russisk
+var norwegian = tr.MustParseTable("no.tsv", noData)
+
 var international = roadSigns
 
+// tieBar is the combining double inverted breve, joining two letters
+const tieBar = "\u0361"
+
+// removeTieBars returns a copy of the table, with tie bars removed from the target strings
+func removeTieBars(t tr.Table) tr.Table {
+	res := tr.Table{Name: t.Name, Classes: t.Classes}
+	for _, r := range t.Rules {
+		r.To = strings.ReplaceAll(r.To, tieBar, "")
+		res.Rules = append(res.Rules, r)
+	}
+	return res
+}
+
 var commonChars = map[string]bool{
 	" ":      true,
 	",":      true,
@@ -85,90 +299,65 @@ var commonChars = map[string]bool{
 	"\u0301": true, // Combining acute accent
 }
 
-// https://tt.se/tt-spraket/ord-och-begrepp/internationellt/andra-sprak/ryska/
-var swePairs = []pair{
-	{s1: "zh", s2: "zj"},
-	{s1: "kh", s2: "ch"},
-	{s1: "ch", s2: "tj"},
-	{s1: "sh", s2: "sj"},
-	//pair{s1: "shch", s2: "sjtj"}, // not needed
-	{s1: "yu", s2: "ju"},
-	{s1: "ya", s2: "ja"},
-	{s1: "ye", s2: "je"}, // ?? will this ever happen?
+func isCommonChar(r rune) bool {
+	return commonChars[string(r)]
 }
 
-var sweREs = []rPair{ // https://tt.se/tt-spraket/ord-och-begrepp/internationellt/andra-sprak/ryska/
+// buildRules adds title case and upper case versions of the rules. Rules for signs without case (e.g. ’ for ь in the reverse rules) are used as they are, and the case of the output is taken from the neighbouring letters (see matchCase).
+func buildRules(table tr.Table) []tr.Rule {
+	res := []tr.Rule{}
+	for _, r := range table.Rules {
+		res = append(res, r)
+		if tr.Upcase(r.From) == r.From {
+			continue
+		}
+		upcaseInitial, upcase := r, r
+		upcaseInitial.From, upcaseInitial.To = tr.UpcaseInitial(r.From), tr.UpcaseInitial(r.To)
+		upcase.From, upcase.To = tr.Upcase(r.From), tr.Upcase(r.To)
+		res = append(res, upcaseInitial, upcase)
+	}
+	return res
+}
 
-	// must be unambiguous for output
+// matchCase upcases title case digraphs (e.g. Shch for Щ) and signs without case (e.g. ʹ for Ь) in all caps words, taking the case from the neighbouring letter
+func matchCase(res tr.Result) tr.Result {
+	return tr.UpcaseSigns(tr.UpcaseDigraphs(res))
+}
 
-	// rPair{from: regexp.MustCompile(`(?:i)ev([\p{P}]|$)`), to: "(j)ev$1"},
-	// rPair{from: regexp.MustCompile(`(?:i)ov([\p{P}]|$)`), to: "ov$1"},
-	// rPair{from: regexp.MustCompile(`(?:i)ev([\p{P}]|$)`), to: "jov$1"}, // Gorbatjov
+// newSystem creates a transliterator for a system or a language specific transcription, with upper case rules added by buildRules
+func newSystem(scheme tr.Scheme, table tr.Table) tr.SystemTranslit {
+	return tr.NewSystemTranslit(tr.SystemConfig{Scheme: scheme, Table: table, Rules: buildRules, IsCommonChar: isCommonChar, Post: matchCase})
+}
 
-	{from: regexp.MustCompile(`(?i)ky(\b|$)`), to: "kij$2"},
-	{from: regexp.MustCompile(`(?i)gy(\b|$)`), to: "gij$2"},
-	{from: regexp.MustCompile(`(?i)ay(\b|$)`), to: "aj$2"},
-	{from: regexp.MustCompile(`(?i)ey(\b|$)`), to: "ej$2"},
-	{from: regexp.MustCompile(`(?i)y(\b|$)`), to: "yj$2"},
+// newRoadSignsSystem creates the road signs transliterator. Since several Cyrillic characters have the same Latin spelling (e.g. е, э and ё are all spelled e), reverse conversion returns ranked candidates.
+func newRoadSignsSystem() tr.SystemTranslit {
+	return tr.NewSystemTranslit(tr.SystemConfig{Scheme: roadSignsScheme, Table: international, Rules: buildRules, IsCommonChar: isCommonChar, Post: matchCase,
+		RevTable: &roadSignsRev, RevTableRules: buildRules, RevIsCommonChar: isCommonChar, RevPost: matchCase})
 }
 
+// systems holds the transliterator of each System
+var systems = []tr.SystemTranslit{
+	RoadSigns:      newRoadSignsSystem(),
+	BGNPCGN:        newSystem(bgnPCGNScheme, bgnPCGN),
+	ALALC:          newSystem(alaLCScheme, alaLC),
+	ALALCNoTieBars: newSystem(alaLCNoTieBarsScheme, alaLCNoTieBars),
+	ICAO:           newSystem(icaoScheme, icao),
+}
+
+// languages holds the transliterator of each target language
+var languages = map[string]tr.SystemTranslit{
+	"sv": newSystem(ttSweScheme, ttSwe),
+	"de": newSystem(dudenDeScheme, german),
+	"fr": newSystem(frScheme, french),
+	"fi": newSystem(fiScheme, finnish),
+	"no": newSystem(noScheme, norwegian),
+}
+
+// Convert converts the input string from Cyrillic to Latin script
 func (translit Translit) Convert(s string) (string, error) {
-	s = tr.NFC(s)
-	intAll := []pair{}
-	for _, p := range international {
-		intAll = append(intAll, p)
-		intAll = append(intAll, pair{s1: tr.UpcaseInitial(p.s1), s2: tr.UpcaseInitial(p.s2)})
-		intAll = append(intAll, pair{s1: tr.Upcase(p.s1), s2: tr.Upcase(p.s2)})
-	}
-	res, err := translit.innerConvert(intAll, s, true)
+	res, err := translit.Transliterate(s)
 	if err != nil {
 		return "", err
 	}
-	if translit.SwedishOutput {
-		sweAll := []pair{}
-		for _, p := range swePairs {
-			sweAll = append(sweAll, p)
-			sweAll = append(sweAll, pair{s1: tr.UpcaseInitial(p.s1), s2: tr.UpcaseInitial(p.s2)})
-			sweAll = append(sweAll, pair{s1: tr.Upcase(p.s1), s2: tr.Upcase(p.s2)})
-		}
-		res, err := translit.innerConvert(sweAll, res, false)
-		if err != nil {
-			return "", err
-		}
-		for _, p := range sweREs {
-			res = p.from.ReplaceAllString(res, p.to)
-		}
-	}
-	return res, nil
-}
-
-func (translit Translit) innerConvert(chsAll []pair, s string, requireAllMapped bool) (string, error) {
-	sOrig := s
-	res := []string{}
-	for len(s) > 0 {
-		sStart := s
-		head := string([]rune(s)[0])
-		for _, p := range chsAll {
-			if strings.HasPrefix(s, p.s1) {
-				res = append(res, p.s2)
-				s = strings.TrimPrefix(s, p.s1)
-				break
-			}
-			// check for common chars
-			if _, ok := commonChars[head]; ok {
-				res = append(res, head)
-				s = strings.TrimPrefix(s, head)
-				break
-			}
-		}
-		if s == sStart { // nothing found to map for this prefix
-			if requireAllMapped {
-				return "", fmt.Errorf("Couldn't convert '%s' in '%s'", s, sOrig)
-			} else if s == sStart {
-				res = append(res, head)
-				s = strings.TrimPrefix(s, head)
-			}
-		}
-	}
-	return strings.Join(res, ""), nil
+	return res.Output, nil
 }
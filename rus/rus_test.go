@@ -0,0 +1,127 @@
+package rus
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var fsExpGot = "Expected: %v got: %v"
+
+// TestLanguageCorpora converts the examples in testdata/<table name> (Cyrillic input and expected output, tab separated) for each target language
+func TestLanguageCorpora(t *testing.T) {
+	for _, lang := range Languages() {
+		tlit, err := NewTranslitLanguage(lang)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		testCorpus(t, tlit, "testdata/"+tlit.Table().Name)
+	}
+}
+
+// TestSystemCorpora converts the examples in testdata/<system name>.tsv for each transliteration system (alalc and alalc-notie share a table)
+func TestSystemCorpora(t *testing.T) {
+	for i := range systemNames {
+		system := System(i)
+		testCorpus(t, NewTranslitSystem(system), "testdata/"+system.String()+".tsv")
+	}
+}
+
+// testCorpus converts the Cyrillic input in the tab separated file fn, and compares the output to the expected output
+func testCorpus(t *testing.T, tlit Translit, fn string) {
+	t.Helper()
+	fh, err := os.Open(fn)
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+		return
+	}
+	defer fh.Close()
+	n := 0
+	scanner := bufio.NewScanner(fh)
+	for scanner.Scan() {
+		fs := strings.Split(scanner.Text(), "\t")
+		if len(fs) != 2 {
+			t.Errorf("%s: expected 2 tab separated fields, got %v", fn, fs)
+			continue
+		}
+		n++
+		res, err := tlit.Transliterate(fs[0])
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != fs[1] {
+			t.Errorf("%s: "+fsExpGot, fn, fs[1], res.Output)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if n == 0 {
+		t.Errorf("%s: no test data found", fn)
+	}
+}
+
+func TestNewTranslitLanguage(t *testing.T) {
+	if _, err := NewTranslitLanguage("xx"); err == nil {
+		t.Errorf("expected error for unknown language")
+	}
+	if res := NewTranslit(true).Scheme().ID; res != ttSweScheme.ID {
+		t.Errorf(fsExpGot, ttSweScheme.ID, res)
+	}
+}
+
+func TestReverse(t *testing.T) {
+	tlit := NewTranslitSystem(RoadSigns)
+	for input, expect := range map[string]string{
+		"Yeltsin":           "Елцин",
+		"Boris Yeltsin":     "Борис Елцин",
+		"Tsar'":             "Царь",
+		"Tsar’":             "Царь",
+		"TSAR'":             "ЦАРЬ",
+		"Yuriy":             "Юрий",
+		"YURIY":             "ЮРИЙ",
+		"Khrushchyov":       "Хрущёв",
+		"Dostoyevskiy":      "Достоевский",
+		"Vasil'yev":         "Васильев",
+		"Yoshkar-Ola":       "Йошкар-Ола",
+		"Krasnye":           "Красные",
+		"Mikhail Gorbachev": "Михаил Горбачев",
+	} {
+		res, err := tlit.Reverse(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+		if last := res.Segments[len(res.Segments)-1]; last.OutputEnd != len(res.Output) {
+			t.Errorf(fsExpGot, len(res.Output), last.OutputEnd)
+		}
+	}
+}
+
+func TestRevert(t *testing.T) {
+	tlit := NewTranslitSystem(RoadSigns)
+	for input, expect := range map[string][]string{
+		"Tsar'": {"Царь", "Тсарь"},
+		"Yo":    {"Йо", "Ё", "Ыо"},
+	} {
+		res, err := tlit.Revert(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if !reflect.DeepEqual(res, expect) {
+			t.Errorf(fsExpGot, expect, res)
+		}
+	}
+
+	if _, err := NewTranslitSystem(ICAO).Revert("Tsar"); err == nil {
+		t.Errorf("expected error for a scheme that isn't reversible")
+	}
+}
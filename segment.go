@@ -0,0 +1,154 @@
+package translit
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// runeOffsets returns the byte offset of each rune in s, followed by len(s)
+func runeOffsets(s string) []int {
+	res := make([]int, 0, utf8.RuneCountInString(s)+1)
+	for i := range s {
+		res = append(res, i)
+	}
+	return append(res, len(s))
+}
+
+// RuneSegments returns a copy of the result segments, with rune offsets instead of byte offsets
+func (res Result) RuneSegments() []Segment {
+	in, out := runeIndex(res.Input), runeIndex(res.Output)
+	segs := make([]Segment, len(res.Segments))
+	for i, seg := range res.Segments {
+		seg.InputStart, seg.InputEnd = in[seg.InputStart], in[seg.InputEnd]
+		seg.OutputStart, seg.OutputEnd = out[seg.OutputStart], out[seg.OutputEnd]
+		segs[i] = seg
+	}
+	return segs
+}
+
+// SegmentAt returns the segment containing the rune offset of the input string (the last segment, if the offset is at the end of the input), or nil if there are no segments
+func (res Result) SegmentAt(offset int) *Segment {
+	if len(res.Segments) == 0 {
+		return nil
+	}
+	pos := len(res.Input)
+	if rs := []rune(res.Input); offset < len(rs) {
+		pos = len(string(rs[:offset]))
+	}
+	for i, seg := range res.Segments {
+		if pos >= seg.InputStart && pos < seg.InputEnd {
+			return &res.Segments[i]
+		}
+	}
+	return &res.Segments[len(res.Segments)-1]
+}
+
+// runeIndex maps the byte offset of each rune in s (and len(s)) to its rune offset
+func runeIndex(s string) map[int]int {
+	res := map[int]int{}
+	for i, offset := range runeOffsets(s) {
+		res[offset] = i
+	}
+	return res
+}
+
+// UpcaseDigraphs returns a copy of the result, where title case digraphs converted from a single upper case input letter (e.g. Lj for Љ) are upcased (LJ), if the next input letter, or the previous one at the end of a word, is upper case too (as in ЉУБА, LJUBA). The result must have segments covering the entire output (see Converter.ConvertSegments).
+func UpcaseDigraphs(res Result) Result {
+	return upcaseSegments(res, isTitleDigraph, 1)
+}
+
+// UpcaseSigns returns a copy of the result, where lower case output converted from input without case (e.g. ь for the sign ʹ) is upcased (Ь), if the next input letter, or the two previous ones at the end of a word, are upper case (as in CARʹ, ЦАРЬ, while Vʺ is Въ). The result must have segments covering the entire output (see Converter.ConvertSegments).
+func UpcaseSigns(res Result) Result {
+	return upcaseSegments(res, isCaselessSign, 2)
+}
+
+// upcaseSegments upcases the output of the segments for which upcase returns true, if the neighbouring input letters are upper case (see neighbourIsUpper)
+func upcaseSegments(res Result, upcase func(from, to string) bool, nPrev int) Result {
+	if len(res.Segments) == 0 {
+		return res
+	}
+	var out strings.Builder
+	segs := make([]Segment, len(res.Segments))
+	for i, seg := range res.Segments {
+		to := res.Output[seg.OutputStart:seg.OutputEnd]
+		if upcase(res.Input[seg.InputStart:seg.InputEnd], to) && neighbourIsUpper(res.Input, seg.InputStart, seg.InputEnd, nPrev) {
+			to = strings.ToUpper(to)
+		}
+		seg.OutputStart = out.Len()
+		out.WriteString(to)
+		seg.OutputEnd = out.Len()
+		segs[i] = seg
+	}
+	return Result{Input: res.Input, Output: out.String(), Segments: segs}
+}
+
+// ComposeOutput returns a copy of the result, with the output composed (NFC). Where combining marks in the output are composed with the preceding letter (e.g. a and \u0301 into á), the segments of the letter and the marks are merged. The result must have segments covering the entire output (see Converter.ConvertSegments).
+func ComposeOutput(res Result) Result {
+	if len(res.Segments) == 0 {
+		return Result{Input: res.Input, Output: NFC(res.Output)}
+	}
+	var out strings.Builder
+	segs := []Segment{}
+	addGroup := func(group []Segment) {
+		joined := res.Output[group[0].OutputStart:group[len(group)-1].OutputEnd]
+		if composed := NFC(joined); composed != joined {
+			seg := Segment{InputStart: group[0].InputStart, InputEnd: group[len(group)-1].InputEnd, OutputStart: out.Len()}
+			out.WriteString(composed)
+			seg.OutputEnd = out.Len()
+			segs = append(segs, seg)
+			return
+		}
+		for _, seg := range group {
+			to := res.Output[seg.OutputStart:seg.OutputEnd]
+			seg.OutputStart = out.Len()
+			out.WriteString(to)
+			seg.OutputEnd = out.Len()
+			segs = append(segs, seg)
+		}
+	}
+	// each group is a segment followed by segments with empty output, or output starting with a combining mark
+	start := 0
+	for i := 1; i <= len(res.Segments); i++ {
+		if i < len(res.Segments) {
+			r, _ := utf8.DecodeRuneInString(res.Output[res.Segments[i].OutputStart:res.Segments[i].OutputEnd])
+			if res.Segments[i].OutputStart == res.Segments[i].OutputEnd || unicode.IsMark(r) {
+				continue
+			}
+		}
+		addGroup(res.Segments[start:i])
+		start = i
+	}
+	return Result{Input: res.Input, Output: out.String(), Segments: segs}
+}
+
+// isTitleDigraph returns true if from is a single upper case letter, and to is two or more letters in title case
+func isTitleDigraph(from, to string) bool {
+	f, t := []rune(from), []rune(to)
+	return len(f) == 1 && unicode.IsUpper(f[0]) && len(t) > 1 && unicode.IsUpper(t[0]) && to != strings.ToUpper(to)
+}
+
+// isCaselessSign returns true if from has no upper or lower case letters, and to has lower case letters
+func isCaselessSign(from, to string) bool {
+	for _, r := range from {
+		if unicode.IsUpper(r) || unicode.IsLower(r) {
+			return false
+		}
+	}
+	return to != strings.ToUpper(to)
+}
+
+// neighbourIsUpper returns true if the letter following the input from start to end is upper case, or, if no letter follows, the nPrev letters preceding it
+func neighbourIsUpper(s string, start, end int, nPrev int) bool {
+	if r, _ := utf8.DecodeRuneInString(s[end:]); end < len(s) && unicode.IsLetter(r) {
+		return unicode.IsUpper(r)
+	}
+	for i := 0; i < nPrev; i++ {
+		r, n := utf8.DecodeLastRuneInString(s[:start])
+		if start == 0 || !unicode.IsUpper(r) {
+			return false
+		}
+		start -= n
+	}
+	return nPrev > 0
+}
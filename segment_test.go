@@ -0,0 +1,151 @@
+package translit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func segmentStrings(input, output string, segs []Segment) [][2]string {
+	res := [][2]string{}
+	for _, s := range segs {
+		res = append(res, [2]string{input[s.InputStart:s.InputEnd], output[s.OutputStart:s.OutputEnd]})
+	}
+	return res
+}
+
+func TestConvertSegments(t *testing.T) {
+	c := NewConverter([]Rule{
+		{From: "ш", To: "sh"},
+		{From: "щ", To: "shch"},
+		{From: "ь", To: ""},
+		{From: "а", To: "a"},
+		{From: "и", To: "i"},
+	}, func(r rune) bool { return r == ' ' })
+	input := "щи ша шь"
+	output, segs, err := c.ConvertSegments(input, true)
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+		return
+	}
+	if output != "shchi sha sh" {
+		t.Errorf(fsExpGot, "shchi sha sh", output)
+	}
+	expect := [][2]string{{"щ", "shch"}, {"и", "i"}, {" ", " "}, {"ш", "sh"}, {"а", "a"}, {" ", " "}, {"ш", "sh"}, {"ь", ""}}
+	if got := segmentStrings(input, output, segs); !reflect.DeepEqual(got, expect) {
+		t.Errorf(fsExpGot, expect, got)
+	}
+	if segs[0].Rule.From != "щ" || segs[2].Rule.From != "" {
+		t.Errorf(fsExpGot, "rules щ and <none>", []Rule{segs[0].Rule, segs[2].Rule})
+	}
+
+	res := Result{Input: input, Output: output, Segments: segs}
+	got := res.RuneSegments()[1]
+	got.Rule = Rule{}
+	if exp := (Segment{InputStart: 1, InputEnd: 2, OutputStart: 4, OutputEnd: 5}); !reflect.DeepEqual(got, exp) {
+		t.Errorf(fsExpGot, exp, got)
+	}
+}
+
+func TestUpcaseDigraphs(t *testing.T) {
+	c := NewConverter([]Rule{
+		{From: "љ", To: "lj"},
+		{From: "Љ", To: "Lj"},
+		{From: "у", To: "u"},
+		{From: "У", To: "U"},
+		{From: "б", To: "b"},
+		{From: "Б", To: "B"},
+		{From: "а", To: "a"},
+		{From: "А", To: "A"},
+	}, func(r rune) bool { return r == ' ' })
+	for input, expect := range map[string]string{
+		"Љуба":      "Ljuba",
+		"ЉУБА":      "LJUBA",
+		"Љ":         "Lj",
+		"УЉ Љ":      "ULJ Lj",
+		"Љуба ЉУБА": "Ljuba LJUBA",
+	} {
+		output, segs, err := c.ConvertSegments(input, true)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		res := UpcaseDigraphs(Result{Input: input, Output: output, Segments: segs})
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+		if last := res.Segments[len(res.Segments)-1]; last.OutputEnd != len(res.Output) {
+			t.Errorf(fsExpGot, len(res.Output), last.OutputEnd)
+		}
+	}
+}
+
+func TestUpcaseSigns(t *testing.T) {
+	c := NewConverter([]Rule{
+		{From: "ʹ", To: "ь"},
+		{From: "c", To: "ц"},
+		{From: "C", To: "Ц"},
+		{From: "a", To: "а"},
+		{From: "A", To: "А"},
+		{From: "r", To: "р"},
+		{From: "R", To: "Р"},
+	}, func(r rune) bool { return r == ' ' })
+	for input, expect := range map[string]string{
+		"carʹ":      "царь",
+		"Carʹ":      "Царь",
+		"CARʹ":      "ЦАРЬ",
+		"ʹA ʹa":     "ЬА ьа",
+		"Cʹ CAʹ":    "Ць ЦАЬ",
+		"Carʹ CARʹ": "Царь ЦАРЬ",
+	} {
+		output, segs, err := c.ConvertSegments(input, true)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		res := UpcaseSigns(Result{Input: input, Output: output, Segments: segs})
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+		if last := res.Segments[len(res.Segments)-1]; last.OutputEnd != len(res.Output) {
+			t.Errorf(fsExpGot, len(res.Output), last.OutputEnd)
+		}
+	}
+}
+
+func TestComposeOutput(t *testing.T) {
+	c := NewConverter([]Rule{
+		{From: "α", To: "a"},
+		{From: "ν", To: "n"},
+		{From: "ρ", To: "r"},
+		{From: "\u0313", To: ""},
+		{From: "\u0301", To: "\u0301"},
+		{From: "\u0342", To: "\u0302"},
+	}, func(r rune) bool { return r == ' ' })
+	for input, expect := range map[string][]string{
+		// decomposed input: output, input of each segment
+		"α\u0301ν":         {"án", "α\u0301", "ν"},
+		"α\u0313\u0301ρ":   {"ár", "α\u0313\u0301", "ρ"},
+		"ν\u0342 α":        {"n\u0302 a", "ν", "\u0342", " ", "α"},
+		"α\u0342ν α\u0313": {"ân a", "α\u0342", "ν", " ", "α", "\u0313"},
+	} {
+		output, segs, err := c.ConvertSegments(input, true)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		res := ComposeOutput(Result{Input: input, Output: output, Segments: segs})
+		if res.Output != expect[0] {
+			t.Errorf(fsExpGot, expect[0], res.Output)
+		}
+		inputs := []string{}
+		for _, seg := range res.Segments {
+			inputs = append(inputs, res.Input[seg.InputStart:seg.InputEnd])
+		}
+		if !reflect.DeepEqual(inputs, expect[1:]) {
+			t.Errorf(fsExpGot, expect[1:], inputs)
+		}
+		if last := res.Segments[len(res.Segments)-1]; last.OutputEnd != len(res.Output) {
+			t.Errorf(fsExpGot, len(res.Output), last.OutputEnd)
+		}
+	}
+}
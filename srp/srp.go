@@ -0,0 +1,53 @@
+package srp
+
+import (
+	_ "embed"
+
+	tr "github.com/stts-se/translit"
+)
+
+// https://en.wikipedia.org/wiki/Serbian_Cyrillic_alphabet
+// Gaj's Latin alphabet, one-to-one with the Cyrillic alphabet
+
+//go:embed tables/gaj.tsv
+var maptableData string
+
+//go:embed tables/gaj-rev.tsv
+var revTableData string
+
+var maptable = tr.MustParseTable("gaj.tsv", maptableData)
+
+// Latin to Cyrillic rules, added to the reversed maptable. Word stems where a digraph (lj, nj, dž) represents two Cyrillic letters, such as injekcija, are matched at the start of a word.
+var revTable = tr.MustParseTable("gaj-rev.tsv", revTableData)
+
+var gajScheme = tr.Scheme{
+	ID:          "sr-Latn-x-gaj",
+	Source:      "sr-Cyrl",
+	Target:      "sr-Latn",
+	Description: "Serbian Cyrillic to Latin script (Gaj's Latin alphabet), and reverse",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Serbian_Cyrillic_alphabet",
+		"https://en.wikipedia.org/wiki/Gaj%27s_Latin_alphabet",
+	},
+}
+
+// Digraphs for upper case letters are written in title case (Lj for Љ), or in upper case (LJ) next to other upper case letters
+var translit = tr.NewTableTranslit(tr.TableConfig{
+	Scheme:         gajScheme,
+	Table:          maptable,
+	RevTable:       &revTable,
+	CommonChars:    "'„“”",
+	UpcaseDigraphs: true,
+})
+
+// Translit converts Serbian Cyrillic to Latin script, and reverse
+type Translit = tr.TableTranslit
+
+// NewTranslit returns a transliterator between Serbian Cyrillic and Latin script
+func NewTranslit() Translit {
+	return translit
+}
+
+func init() {
+	tr.Register(gajScheme, func() tr.Transliterator { return NewTranslit() })
+}
@@ -0,0 +1,60 @@
+package srp
+
+import (
+	"testing"
+
+	tr "github.com/stts-se/translit"
+)
+
+var fsExpGot = "Expected: %v got: %v"
+
+var tests = map[string]string{
+	"Љубав Њива Џеп":        "Ljubav Njiva Džep",
+	"ЉУБАВ ЊИВА ЏЕП":        "LJUBAV NJIVA DŽEP",
+	"ЂОРЂЕ Ђорђе ћерка":     "ĐORĐE Đorđe ćerka",
+	"инјекција конјункција": "injekcija konjunkcija",
+	"Инјекција ИНЈЕКЦИЈА":   "Injekcija INJEKCIJA",
+	"надживети поджанр":     "nadživeti podžanr",
+	"Танјуг, Београд":       "Tanjug, Beograd",
+	"чаша шећера, жена":     "čaša šećera, žena",
+	// exception stems (tables/gaj-rev.tsv) only at the start of a word, not odžal in hodžaluk
+	"хоџалук, Хоџалук": "hodžaluk, Hodžaluk",
+	"ХОЏАЛУК оджалити": "HODŽALUK odžaliti",
+}
+
+func TestTransliterate(t *testing.T) {
+	tlit := NewTranslit()
+	for input, expect := range tests {
+		res, err := tlit.Transliterate(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	tlit := NewTranslit()
+	for expect, input := range tests {
+		res, err := tlit.Reverse(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+		if err := tr.VerifyRoundTrip(tlit, expect); err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		}
+	}
+	res, err := tlit.Reverse("ǈubav")
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	} else if res.Output != "Љубав" {
+		t.Errorf(fsExpGot, "Љубав", res.Output)
+	}
+}
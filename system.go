@@ -0,0 +1,214 @@
+package translit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SystemNames are the names of the romanisation systems of a package, in index order, for use in flag.Value implementations of the package's System type
+type SystemNames []string
+
+// Name returns the name of the system with index i
+func (names SystemNames) Name(i int) string {
+	if i < 0 || i >= len(names) {
+		return fmt.Sprintf("System(%d)", i)
+	}
+	return names[i]
+}
+
+// Index returns the index of the named system
+func (names SystemNames) Index(name string) (int, error) {
+	for i, n := range names {
+		if n == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid system '%s' (expected %s)", name, strings.Join(names, ", "))
+}
+
+// SystemConfig holds the settings of a SystemTranslit. A reversible system has either RevRules, for one-to-one reverse conversion, or a RevTable, for reverse conversion with ranked candidates.
+type SystemConfig struct {
+	Scheme       Scheme
+	Table        Table                   // Source to target mapping table
+	Rules        func(t Table) []Rule    // Builds the conversion rules from the mapping table, e.g. adding upper case versions (nil: the table rules as they are)
+	IsCommonChar func(r rune) bool       // Characters accepted in the input without a rule (may be nil)
+	Normalise    func(s string) string   // Input normalisation (nil: NFC, or NFD for decomposed systems)
+	Decomposed   bool                    // The mapping table is written for decomposed (NFD) input, and the output is composed (see ComposeOutput)
+	Post         func(res Result) Result // Applied to the conversion result, e.g. UpcaseDigraphs (may be nil)
+
+	RevRules        func(rules []Rule) []Rule // Builds the one-to-one reverse rules from the conversion rules (the first rule wins)
+	RevTable        *Table                    // Target to source rules, where rules with the same source string are alternatives, most likely first (see NewCandidateConverter)
+	RevTableRules   func(t Table) []Rule      // Builds the reverse rules from RevTable, e.g. adding upper case versions (nil: the table rules as they are)
+	RevIsCommonChar func(r rune) bool         // Characters accepted in the reverse input without a rule (may be nil)
+	RevPost         func(res Result) Result   // Applied to each reverse candidate (may be nil)
+}
+
+// SystemTranslit is a transliterator for a romanisation system defined by a mapping table and functions building the conversion rules, with optional reverse conversion, one-to-one or with ranked candidates
+type SystemTranslit struct {
+	config        SystemConfig
+	converter     Converter
+	revConverter  *Converter
+	revCandidates *CandidateConverter
+	unknown       UnknownPolicy
+}
+
+// NewSystemTranslit creates a transliterator for the config. The converters are built once, so the transliterator is typically created at package initialisation.
+func NewSystemTranslit(config SystemConfig) SystemTranslit {
+	res := SystemTranslit{config: config}
+	rules := config.buildRules(config.Table)
+	res.converter = NewConverter(rules, config.IsCommonChar)
+	switch {
+	case config.RevRules != nil:
+		c := NewConverter(config.RevRules(rules), config.RevIsCommonChar)
+		res.revConverter = &c
+	case config.RevTable != nil:
+		revRules := config.RevTable.Rules
+		if config.RevTableRules != nil {
+			revRules = config.RevTableRules(*config.RevTable)
+		}
+		c := NewCandidateConverter(revRules, config.RevIsCommonChar)
+		res.revCandidates = &c
+	}
+	return res
+}
+
+var _ Extensible = SystemTranslit{}
+var _ UnknownHandler = SystemTranslit{}
+var _ CandidateReverser = SystemTranslit{}
+
+func (config SystemConfig) buildRules(t Table) []Rule {
+	if config.Rules == nil {
+		return t.Rules
+	}
+	return config.Rules(t)
+}
+
+// Scheme returns the scheme metadata
+func (translit SystemTranslit) Scheme() Scheme {
+	return translit.config.Scheme
+}
+
+// Table returns the mapping table (source to target)
+func (translit SystemTranslit) Table() Table {
+	return translit.config.Table
+}
+
+// Decomposed returns true for systems with a mapping table for decomposed (NFD) input (see IsDecomposed)
+func (translit SystemTranslit) Decomposed() bool {
+	return translit.config.Decomposed
+}
+
+func (translit SystemTranslit) normalise(s string) string {
+	switch {
+	case translit.config.Normalise != nil:
+		return translit.config.Normalise(s)
+	case translit.config.Decomposed:
+		return NFD(s)
+	}
+	return NFC(s)
+}
+
+// Transliterate converts the input string from the source script into the target script. The Input of the result is the normalised input string.
+func (translit SystemTranslit) Transliterate(s string) (Result, error) {
+	s = translit.normalise(s)
+	out, segs, err := translit.converter.WithUnknownPolicy(translit.unknown).ConvertSegments(s, true)
+	res := Result{Input: s, Output: out, Segments: segs}
+	if err != nil {
+		return res, WithScheme(err, translit.Scheme())
+	}
+	if translit.config.Decomposed {
+		res = ComposeOutput(res)
+	}
+	if translit.config.Post != nil {
+		res = translit.config.Post(res)
+	}
+	return res, nil
+}
+
+// Reversible returns true if the system can be converted back
+func (translit SystemTranslit) Reversible() bool {
+	return translit.revConverter != nil || translit.revCandidates != nil
+}
+
+// ReverseCandidates converts the input string from the target script into the source script, and returns at most max candidate spellings, most likely first. For one-to-one reverse conversion, there is only one candidate.
+func (translit SystemTranslit) ReverseCandidates(s string, max int) ([]Candidate, error) {
+	if !translit.Reversible() {
+		return nil, fmt.Errorf("scheme %s is not reversible", translit.Scheme())
+	}
+	s = NFC(s)
+	var res []Candidate
+	if translit.revConverter != nil {
+		output, segs, err := translit.revConverter.WithUnknownPolicy(translit.unknown).ConvertSegments(s, true)
+		if err != nil {
+			return nil, WithScheme(err, translit.Scheme())
+		}
+		res = []Candidate{{Output: output, Segments: segs}}
+	} else {
+		var err error
+		res, err = translit.revCandidates.WithUnknownPolicy(translit.unknown).Candidates(s, max)
+		if err != nil {
+			return res, WithScheme(err, translit.Scheme())
+		}
+	}
+	if post := translit.config.RevPost; post != nil {
+		for i, c := range res {
+			r := post(Result{Input: s, Output: c.Output, Segments: c.Segments})
+			res[i].Output, res[i].Segments = r.Output, r.Segments
+		}
+	}
+	return res, nil
+}
+
+// Revert converts the input string from the target script into the source script, and returns all candidate spellings (up to DefaultMaxCandidates), most likely first
+func (translit SystemTranslit) Revert(s string) ([]string, error) {
+	cands, err := translit.ReverseCandidates(s, DefaultMaxCandidates)
+	if err != nil {
+		return nil, err
+	}
+	res := []string{}
+	for _, c := range cands {
+		res = append(res, c.Output)
+	}
+	return res, nil
+}
+
+// Reverse converts the input string from the target script into the source script, using the most likely candidate (see Revert)
+func (translit SystemTranslit) Reverse(s string) (Result, error) {
+	s = NFC(s)
+	cands, err := translit.ReverseCandidates(s, 1)
+	if err != nil {
+		return Result{Input: s}, err
+	}
+	return Result{Input: s, Output: cands[0].Output, Segments: cands[0].Segments}, nil
+}
+
+// Extend returns a copy of the transliterator, with the rules of the input table added to the mapping table. The rules are built by the config (e.g. adding upper case versions), and the reverse conversion is updated too: one-to-one reverse rules are rebuilt from the merged table, and for reverse conversion with candidates, the reversed rules of the input table are ranked before the rules of the reverse table.
+func (translit SystemTranslit) Extend(t Table) (Transliterator, error) {
+	config := translit.config
+	config.Table = config.Table.Merge(t)
+	if config.RevTable != nil {
+		rev := *config.RevTable
+		rev.Rules = append(swapRules(t.Rules), rev.Rules...)
+		config.RevTable = &rev
+	}
+	res := NewSystemTranslit(config)
+	res.unknown = translit.unknown
+	return res, nil
+}
+
+// swapRules swaps the source and target of each rule, skipping rules with an empty target. Contexts are dropped, since they apply to the source script.
+func swapRules(rules []Rule) []Rule {
+	res := []Rule{}
+	for _, r := range rules {
+		if r.To != "" {
+			res = append(res, Rule{From: NFC(r.To), To: r.From})
+		}
+	}
+	return res
+}
+
+// WithUnknownPolicy returns a copy of the transliterator, using the policy for unknown input characters
+func (translit SystemTranslit) WithUnknownPolicy(p UnknownPolicy) Transliterator {
+	translit.unknown = p
+	return translit
+}
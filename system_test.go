@@ -0,0 +1,102 @@
+package translit
+
+import (
+	"testing"
+)
+
+var testCandTable = MustParseTable("test-cand.tsv", "i\tи\ni\tы\nb\tб\nl\tл\n")
+
+func TestSystemTranslit(t *testing.T) {
+	tlit := NewSystemTranslit(SystemConfig{
+		Scheme:       Scheme{ID: "test"},
+		Table:        testTable,
+		Rules:        caseRules,
+		IsCommonChar: commonChars(DefaultCommonChars),
+		Post:         UpcaseDigraphs,
+		RevRules: func(rules []Rule) []Rule {
+			return swapRules(rules)
+		},
+	})
+	for input, expect := range map[string]string{
+		"жаба":      "zhaba",
+		"ЖАБА":      "ZHABA",
+		"Љуба, Љуб": "Ljuba, Ljub",
+	} {
+		res, err := tlit.Transliterate(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+	}
+	if _, err := tlit.Transliterate("жд"); err == nil {
+		t.Errorf(fsExpGot, "error for unmapped д", err)
+	}
+
+	cands, err := tlit.Revert("Zhaba")
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if len(cands) != 1 || cands[0] != "Жаба" {
+		t.Errorf(fsExpGot, []string{"Жаба"}, cands)
+	}
+}
+
+func TestSystemTranslitCandidates(t *testing.T) {
+	tlit := NewSystemTranslit(SystemConfig{
+		Scheme:          Scheme{ID: "test"},
+		Table:           testTable,
+		Rules:           caseRules,
+		RevTable:        &testCandTable,
+		RevTableRules:   caseRules,
+		RevIsCommonChar: commonChars(DefaultCommonChars),
+	})
+	cands, err := tlit.Revert("Bil")
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if len(cands) != 2 || cands[0] != "Бил" || cands[1] != "Был" {
+		t.Errorf(fsExpGot, []string{"Бил", "Был"}, cands)
+	}
+
+	ext, err := tlit.Extend(MustParseTable("ext.tsv", "д\td\nы\ty\n"))
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+		return
+	}
+	res, err := ext.(Reverser).Reverse("dyl")
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if res.Output != "дыл" {
+		t.Errorf(fsExpGot, "дыл", res.Output)
+	}
+	if _, err := tlit.Reverse("dil"); err == nil {
+		t.Errorf(fsExpGot, "error for unmapped d in the original transliterator", err)
+	}
+}
+
+func TestSystemTranslitNotReversible(t *testing.T) {
+	tlit := NewSystemTranslit(SystemConfig{Scheme: Scheme{ID: "test"}, Table: testTable})
+	if IsReversible(tlit) {
+		t.Errorf(fsExpGot, false, true)
+	}
+	if _, err := tlit.Reverse("zhaba"); err == nil {
+		t.Errorf(fsExpGot, "error", err)
+	}
+}
+
+func TestSystemNames(t *testing.T) {
+	names := SystemNames{"a", "b"}
+	if i, err := names.Index("b"); err != nil || i != 1 {
+		t.Errorf(fsExpGot, 1, i)
+	}
+	if _, err := names.Index("c"); err == nil {
+		t.Errorf(fsExpGot, "error", err)
+	}
+	if got := names.Name(2); got != "System(2)" {
+		t.Errorf(fsExpGot, "System(2)", got)
+	}
+}
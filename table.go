@@ -0,0 +1,237 @@
+package translit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Table is a list of mapping rules, typically read from a mapping table file.
+//
+// Mapping table files are plain text files, with one rule per line, and tab separated columns:
+//
+//	source <TAB> target [<TAB> comment [<TAB> context]]
+//
+// Empty lines, and lines starting with #, are ignored. The target may be empty (deleting the source string). Characters that are hard to read or edit, such as combining marks and zero width characters, can be written as \uXXXX escapes (use \\ for a literal backslash).
+//
+// The optional context column restricts the rule to a context (see Context). Character classes used in contexts are defined on lines starting with #class, followed by the class name and the characters of the class, separated by white space:
+//
+//	#class vowel aeiou
+//	s	z		{vowel}_{vowel}
+type Table struct {
+	Name    string
+	Classes map[string]string // Character classes for use in contexts
+	Rules   []Rule
+}
+
+const tableCommentPrefix = "#"
+const tableClassPrefix = "#class"
+
+// ParseTable reads a mapping table from r. The name is used in error messages and the resulting table.
+func ParseTable(name string, r io.Reader) (Table, error) {
+	res := Table{Name: name}
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if fs := strings.Fields(line); len(fs) > 0 && fs[0] == tableClassPrefix {
+			if len(fs) != 3 {
+				return res, fmt.Errorf("%s:%d: expected %s <name> <characters>", name, lineNo, tableClassPrefix)
+			}
+			chars, err := unescape(fs[2])
+			if err != nil {
+				return res, fmt.Errorf("%s:%d: %v", name, lineNo, err)
+			}
+			if res.Classes == nil {
+				res.Classes = map[string]string{}
+			}
+			res.Classes[fs[1]] = chars
+			continue
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, tableCommentPrefix) {
+			continue
+		}
+		fs := strings.Split(line, "\t")
+		if len(fs) < 2 || len(fs) > 4 {
+			return res, fmt.Errorf("%s:%d: expected 2-4 tab separated fields, found %d", name, lineNo, len(fs))
+		}
+		var err error
+		rule := Rule{}
+		if rule.From, err = unescape(fs[0]); err != nil {
+			return res, fmt.Errorf("%s:%d: %v", name, lineNo, err)
+		}
+		if rule.From == "" {
+			return res, fmt.Errorf("%s:%d: empty source string", name, lineNo)
+		}
+		if rule.To, err = unescape(fs[1]); err != nil {
+			return res, fmt.Errorf("%s:%d: %v", name, lineNo, err)
+		}
+		if len(fs) > 2 {
+			rule.Comment = strings.TrimSpace(fs[2])
+		}
+		if len(fs) > 3 {
+			if rule.Context, err = ParseContext(strings.TrimSpace(fs[3]), res.Classes); err != nil {
+				return res, fmt.Errorf("%s:%d: %v", name, lineNo, err)
+			}
+		}
+		res.Rules = append(res.Rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return res, fmt.Errorf("failed to read '%s' : %v", name, err)
+	}
+	return res, nil
+}
+
+// MustParseTable is like ParseTable, but reads the table from a string and panics on error. It is intended for tables embedded in the source code.
+func MustParseTable(name string, data string) Table {
+	t, err := ParseTable(name, strings.NewReader(data))
+	if err != nil {
+		panic(fmt.Sprintf("translit: couldn't parse table: %v", err))
+	}
+	return t
+}
+
+// ReadTable reads a mapping table file
+func ReadTable(fn string) (Table, error) {
+	fn = filepath.Clean(fn)
+	fh, err := os.Open(fn)
+	if err != nil {
+		return Table{}, fmt.Errorf("failed to read '%s' : %v", fn, err)
+	}
+	defer fh.Close()
+	return ParseTable(fn, fh)
+}
+
+// LoadConverter reads a mapping table file, and creates a converter from its rules (see NewConverter)
+func LoadConverter(fn string, isCommonChar func(r rune) bool) (Converter, error) {
+	t, err := ReadTable(fn)
+	if err != nil {
+		return Converter{}, err
+	}
+	return NewConverter(t.Rules, isCommonChar), nil
+}
+
+func (r Rule) key() string {
+	return r.From + "\t" + r.Context.String()
+}
+
+// Merge returns a new table with the rules and character classes of the input table added. Rules with the same source string and context as an existing rule replace the existing rule, other rules are appended at the end.
+func (t Table) Merge(other Table) Table {
+	res := Table{Name: t.Name, Rules: make([]Rule, len(t.Rules))}
+	copy(res.Rules, t.Rules)
+	if len(t.Classes)+len(other.Classes) > 0 {
+		res.Classes = map[string]string{}
+		for k, v := range t.Classes {
+			res.Classes[k] = v
+		}
+		for k, v := range other.Classes {
+			res.Classes[k] = v
+		}
+	}
+	index := map[string]int{}
+	for i, r := range res.Rules {
+		if _, ok := index[r.key()]; !ok {
+			index[r.key()] = i
+		}
+	}
+	for _, r := range other.Rules {
+		if i, ok := index[r.key()]; ok {
+			res.Rules[i] = r
+		} else {
+			index[r.key()] = len(res.Rules)
+			res.Rules = append(res.Rules, r)
+		}
+	}
+	return res
+}
+
+// Write writes the table in mapping table file format
+func (t Table) Write(w io.Writer) error {
+	if t.Name != "" {
+		if _, err := fmt.Fprintf(w, "%s %s\n", tableCommentPrefix, t.Name); err != nil {
+			return err
+		}
+	}
+	classes := []string{}
+	for name := range t.Classes {
+		classes = append(classes, name)
+	}
+	sort.Strings(classes)
+	for _, name := range classes {
+		if _, err := fmt.Fprintf(w, "%s %s %s\n", tableClassPrefix, name, escape(t.Classes[name])); err != nil {
+			return err
+		}
+	}
+	for _, r := range t.Rules {
+		line := escape(r.From) + "\t" + escape(r.To)
+		if strings.HasPrefix(line, tableCommentPrefix) {
+			line = codeFor('#') + line[1:]
+		}
+		if r.Comment != "" || !r.Context.IsEmpty() {
+			line += "\t" + r.Comment
+		}
+		if !r.Context.IsEmpty() {
+			line += "\t" + r.Context.String()
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func needsEscape(r rune) bool {
+	return r <= 0xFFFF && (r == '\t' || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Cc, unicode.Zs))
+}
+
+func escape(s string) string {
+	var res strings.Builder
+	for _, r := range s {
+		if r == '\\' {
+			res.WriteString(`\\`)
+		} else if r == ' ' {
+			res.WriteRune(r)
+		} else if needsEscape(r) {
+			res.WriteString(codeFor(r))
+		} else {
+			res.WriteRune(r)
+		}
+	}
+	return res.String()
+}
+
+func unescape(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var res strings.Builder
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		if rs[i] != '\\' {
+			res.WriteRune(rs[i])
+			continue
+		}
+		if i+1 < len(rs) && rs[i+1] == '\\' {
+			res.WriteRune('\\')
+			i++
+			continue
+		}
+		if i+5 < len(rs) && rs[i+1] == 'u' {
+			n, err := strconv.ParseUint(string(rs[i+2:i+6]), 16, 32)
+			if err == nil {
+				res.WriteRune(rune(n))
+				i += 5
+				continue
+			}
+		}
+		return "", fmt.Errorf("invalid escape sequence in '%s'", s)
+	}
+	return res.String(), nil
+}
@@ -0,0 +1,72 @@
+package translit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTable(t *testing.T) {
+	data := `# test table
+# source	target	comment
+
+a	b
+sh	ʃ	postalveolar
+é	é	combining acute
+\\	/
+x		deleted
+`
+	table, err := ParseTable("test", strings.NewReader(data))
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+		return
+	}
+	expect := []Rule{
+		{From: "a", To: "b"},
+		{From: "sh", To: "ʃ", Comment: "postalveolar"},
+		{From: "é", To: "é", Comment: "combining acute"},
+		{From: `\`, To: "/"},
+		{From: "x", To: "", Comment: "deleted"},
+	}
+	if !reflect.DeepEqual(table.Rules, expect) {
+		t.Errorf(fsExpGot, expect, table.Rules)
+	}
+
+	var w strings.Builder
+	if err := table.Write(&w); err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	table2, err := ParseTable("test", strings.NewReader(w.String()))
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if !reflect.DeepEqual(table2.Rules, expect) {
+		t.Errorf(fsExpGot, expect, table2.Rules)
+	}
+}
+
+func TestParseTableErrors(t *testing.T) {
+	for _, data := range []string{
+		"a",
+		"a\tb\tc\td\te",
+		"\tb",
+		"a\\x\tb",
+	} {
+		if _, err := ParseTable("test", strings.NewReader(data)); err == nil {
+			t.Errorf("expected error for '%s'", data)
+		}
+	}
+}
+
+func TestTableMerge(t *testing.T) {
+	t1 := Table{Name: "t1", Rules: []Rule{{From: "a", To: "1"}, {From: "b", To: "2"}}}
+	t2 := Table{Name: "t2", Rules: []Rule{{From: "c", To: "3"}, {From: "a", To: "4"}}}
+	res := t1.Merge(t2)
+	expect := Table{Name: "t1", Rules: []Rule{{From: "a", To: "4"}, {From: "b", To: "2"}, {From: "c", To: "3"}}}
+	if !reflect.DeepEqual(res, expect) {
+		t.Errorf(fsExpGot, expect, res)
+	}
+	if t1.Rules[0].To != "1" {
+		t.Errorf("Merge should not modify the original table")
+	}
+}
@@ -0,0 +1,124 @@
+package translit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TableConfig holds the settings of a TableTranslit
+type TableConfig struct {
+	Scheme         Scheme
+	Table          Table  // Source to target mapping table, with rules in lower case (upper case versions are added automatically)
+	RevTable       *Table // Target to source rules, used before the reversed rules of the mapping table, or nil if the scheme isn't reversible
+	CommonChars    string // Characters accepted in the input without a rule, in addition to digits and DefaultCommonChars
+	UpcaseDigraphs bool   // Upcase title case digraphs next to upper case letters (see UpcaseDigraphs)
+}
+
+// DefaultCommonChars are the characters accepted in the input of a TableTranslit without a rule, in addition to the digits: white space, punctuation, and the combining acute accent (stress mark)
+const DefaultCommonChars = " \t,.?!–-:;+\"()\u0301"
+
+// TableTranslit is a transliterator for schemes defined by a mapping table only, such as most of the national romanisation systems of Cyrillic. If the config has a reverse table, the reversed rules of the mapping table are used for reverse conversion.
+type TableTranslit struct {
+	config       TableConfig
+	converter    Converter
+	revConverter Converter
+	unknown      UnknownPolicy
+}
+
+// NewTableTranslit creates a transliterator for the config. The converters are built once, so the transliterator is typically created at package initialisation.
+func NewTableTranslit(config TableConfig) TableTranslit {
+	res := TableTranslit{config: config}
+	isCommonChar := commonChars(DefaultCommonChars + config.CommonChars)
+	res.converter = NewConverter(caseRules(config.Table), isCommonChar)
+	if config.RevTable != nil {
+		res.revConverter = NewConverter(revTableRules(config.Table, *config.RevTable), isCommonChar)
+	}
+	return res
+}
+
+var _ Reverser = TableTranslit{}
+var _ Extensible = TableTranslit{}
+var _ UnknownHandler = TableTranslit{}
+
+// commonChars returns a function accepting the ASCII digits, and the characters in chars (see NewConverter)
+func commonChars(chars string) func(r rune) bool {
+	return func(r rune) bool {
+		return (r >= '0' && r <= '9') || strings.ContainsRune(chars, r)
+	}
+}
+
+// caseRules returns the rules of the table, each one followed by its title case and upper case versions
+func caseRules(table Table) []Rule {
+	res := []Rule{}
+	for _, r := range table.Rules {
+		res = append(res, r)
+		upcaseInitial, upcase := r, r
+		upcaseInitial.From, upcaseInitial.To = UpcaseInitial(r.From), UpcaseInitial(r.To)
+		upcase.From, upcase.To = Upcase(r.From), Upcase(r.To)
+		res = append(res, upcaseInitial, upcase)
+	}
+	return res
+}
+
+// revTableRules returns the rules of the rev table, followed by the reversed rules of the table
+func revTableRules(table, rev Table) []Rule {
+	res := caseRules(rev)
+	for _, r := range caseRules(table) {
+		res = append(res, Rule{From: r.To, To: r.From})
+	}
+	return res
+}
+
+// Scheme returns the scheme metadata
+func (translit TableTranslit) Scheme() Scheme {
+	return translit.config.Scheme
+}
+
+// Transliterate converts the input string from the source script into the target script
+func (translit TableTranslit) Transliterate(s string) (Result, error) {
+	s = NFC(s)
+	out, segs, err := translit.converter.WithUnknownPolicy(translit.unknown).ConvertSegments(s, true)
+	res := Result{Input: s, Output: out, Segments: segs}
+	if err != nil {
+		return res, WithScheme(err, translit.Scheme())
+	}
+	if translit.config.UpcaseDigraphs {
+		return UpcaseDigraphs(res), nil
+	}
+	return res, nil
+}
+
+// Reversible returns true if the transliterator has a reverse table
+func (translit TableTranslit) Reversible() bool {
+	return translit.config.RevTable != nil
+}
+
+// Reverse converts the input string from the target script back into the source script
+func (translit TableTranslit) Reverse(s string) (Result, error) {
+	if !translit.Reversible() {
+		return Result{}, fmt.Errorf("scheme %s is not reversible", translit.Scheme())
+	}
+	s = NFC(s)
+	out, segs, err := translit.revConverter.WithUnknownPolicy(translit.unknown).ConvertSegments(s, true)
+	return Result{Input: s, Output: out, Segments: segs}, WithScheme(err, translit.Scheme())
+}
+
+// Table returns the mapping table (source to target)
+func (translit TableTranslit) Table() Table {
+	return translit.config.Table
+}
+
+// Extend returns a copy of the transliterator, with the rules of the input table added to the mapping table. Rules are expected in lower case, upper case versions are added automatically. For reversible schemes, the rules are also used for reverse conversion.
+func (translit TableTranslit) Extend(t Table) (Transliterator, error) {
+	config := translit.config
+	config.Table = config.Table.Merge(t)
+	res := NewTableTranslit(config)
+	res.unknown = translit.unknown
+	return res, nil
+}
+
+// WithUnknownPolicy returns a copy of the transliterator, using the policy for unknown input characters
+func (translit TableTranslit) WithUnknownPolicy(p UnknownPolicy) Transliterator {
+	translit.unknown = p
+	return translit
+}
@@ -0,0 +1,99 @@
+package translit
+
+import (
+	"testing"
+)
+
+var testTable = MustParseTable("test.tsv", "ж\tzh\nл\tl\nљ\tlj\nу\tu\nб\tb\nа\ta\nи\ti\nн\tn\nј\tj\n")
+
+var testRevTable = MustParseTable("test-rev.tsv", "inj\tинј\n")
+
+func TestTableTranslit(t *testing.T) {
+	tlit := NewTableTranslit(TableConfig{
+		Scheme:         Scheme{ID: "test"},
+		Table:          testTable,
+		RevTable:       &testRevTable,
+		CommonChars:    "'",
+		UpcaseDigraphs: true,
+	})
+	for input, expect := range map[string]string{
+		"жаба":      "zhaba",
+		"Жаба":      "Zhaba",
+		"ЉУБА, Љуб": "LJUBA, Ljub",
+		"инјун 12":  "injun 12",
+		"ж'аба":     "zh'aba",
+	} {
+		res, err := tlit.Transliterate(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+	}
+
+	for input, expect := range map[string]string{
+		"Ljuba": "Љуба",
+		"LJUBA": "ЉУБА",
+		"injun": "инјун",
+		"ljin":  "љин",
+	} {
+		res, err := tlit.Reverse(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+	}
+
+	if _, err := tlit.Transliterate("жд"); err == nil {
+		t.Errorf(fsExpGot, "error for unmapped д", err)
+	}
+}
+
+func TestTableTranslitNotReversible(t *testing.T) {
+	tlit := NewTableTranslit(TableConfig{Scheme: Scheme{ID: "test"}, Table: testTable})
+	if IsReversible(tlit) {
+		t.Errorf(fsExpGot, false, true)
+	}
+	if _, err := tlit.Reverse("zhaba"); err == nil {
+		t.Errorf(fsExpGot, "error", err)
+	}
+	// without UpcaseDigraphs, digraphs are written in title case
+	res, err := tlit.Transliterate("ЖУЛ")
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if res.Output != "ZhUL" {
+		t.Errorf(fsExpGot, "ZhUL", res.Output)
+	}
+}
+
+func TestTableTranslitExtend(t *testing.T) {
+	tlit := NewTableTranslit(TableConfig{Scheme: Scheme{ID: "test"}, Table: testTable, RevTable: &testRevTable})
+	ext, err := tlit.Extend(MustParseTable("ext.tsv", "д\td\nж\tž\n"))
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+		return
+	}
+	res, err := ext.Transliterate("Жд")
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if res.Output != "Žd" {
+		t.Errorf(fsExpGot, "Žd", res.Output)
+	}
+	rev, err := ext.(Reverser).Reverse("Žd")
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if rev.Output != "Жд" {
+		t.Errorf(fsExpGot, "Жд", rev.Output)
+	}
+	if _, err := tlit.Transliterate("д"); err == nil {
+		t.Errorf(fsExpGot, "error for unmapped д in the original transliterator", err)
+	}
+}
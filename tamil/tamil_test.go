@@ -1,11 +1,17 @@
 package tamil
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"testing/iotest"
+
+	"github.com/stts-se/translit"
+	"golang.org/x/text/transform"
 )
 
 func imports2() { // keep imports
@@ -137,6 +143,34 @@ func TestTranslitConvertFailReverseTest(t *testing.T) {
 
 }
 
+func TestReverseTestErrors(t *testing.T) {
+	var tlitASCII = NewTranslit()
+	tlitASCII.alwaysAcceptASCII = true
+
+	// the ASCII a is accepted as it is, but read back as a Tamil vowel
+	_, _, _, err := tlitASCII.translit(tlitASCII.theTree, tlitASCII.revTree, []rune("பிசுகளில்a"), true)
+	var rte *translit.RoundTripError
+	if !errors.As(err, &rte) {
+		t.Errorf("Expected *translit.RoundTripError, got: %#v", err)
+	} else if rte.Scheme != iso15919Scheme.ID {
+		t.Errorf("Expected %s, got: %s", iso15919Scheme.ID, rte.Scheme)
+	}
+
+	// the mapped string can't be converted back
+	err = tlit.reverseTest(tlit.revTree, "பிசு", "picuX")
+	var e *translit.Error
+	if !errors.As(err, &e) {
+		t.Errorf("Expected *translit.Error, got: %#v", err)
+	} else if e.Rune != 'X' {
+		t.Errorf("Expected 'X', got: %q", e.Rune)
+	}
+
+	_, err = tlit.Transliterate("பிசுX")
+	if !errors.As(err, &e) {
+		t.Errorf("Expected *translit.Error, got: %#v", err)
+	}
+}
+
 func TestTranslitConvertWithReverseTest(t *testing.T) {
 
 	var tlitASCII = NewTranslit()
@@ -267,3 +301,54 @@ func TestTranslitConvertNumerals(t *testing.T) {
 	testConvertExpectOKWithRes(t, s, "1 2 3 45645678012013 54: po 15- ruḷāta")
 
 }
+
+func TestTranslitSegments(t *testing.T) {
+	var s = "பிசுகளில்"
+	res, err := tlit.Transliterate(s)
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	expect := []string{"பி:pi", "சு:cu", "க:ka", "ளி:ḷi", "ல்:l"}
+	result := []string{}
+	for _, seg := range res.Segments {
+		result = append(result, res.Input[seg.InputStart:seg.InputEnd]+":"+res.Output[seg.OutputStart:seg.OutputEnd])
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("Expected %#v, got %#v", expect, result)
+	}
+
+	res, _ = tlit.Reverse("picukaḷil")
+	if last := res.Segments[len(res.Segments)-1]; last.Rule.From != "l" || last.Rule.To != "ல்" {
+		t.Errorf("Expected %#v, got %#v", "l -> ல்", last.Rule.From+" -> "+last.Rule.To)
+	}
+}
+
+func TestTranslitTransformer(t *testing.T) {
+	var s = "பிசுகளில் பிசுகளில்\nபிசுகளில்"
+	var expect = "picukaḷil picukaḷil\npicukaḷil"
+	r := transform.NewReader(iotest.OneByteReader(strings.NewReader(s)), translit.NewTransformer(tlit))
+	result, err := io.ReadAll(r)
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if string(result) != expect {
+		t.Errorf("Expected %#v, got %#v", expect, string(result))
+	}
+}
+
+func TestTranslitUnknownPolicy(t *testing.T) {
+	var s = "பிசுகளில்x"
+	for policy, expect := range map[string]string{"replace:_": "picukaḷil_", "pass": "picukaḷilx", "drop": "picukaḷil", "fail": ""} {
+		p, err := translit.ParseUnknownPolicy(policy)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		}
+		res, err := tlit.WithUnknownPolicy(p).Transliterate(s)
+		if (err != nil) != (policy == "fail") {
+			t.Errorf("Unexpected error for policy %s: %v", policy, err)
+		}
+		if res.Output != expect {
+			t.Errorf("Expected %#v, got %#v", expect, res.Output)
+		}
+	}
+}
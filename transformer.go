@@ -0,0 +1,78 @@
+package translit
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// NewTransformer returns a transform.Transformer converting text using the transliterator, for streaming conversion with transform.NewReader, transform.NewWriter, etc.
+//
+// The input is converted in chunks, split after white space, and line breaks are copied to the output as they are. Input that doesn't end with white space is kept until more input is available, so that multi-character rules and contexts are never split between buffers. As a consequence, a single word can't be longer than the transform buffer (4096 bytes for transform.NewReader). The first conversion error is returned as is.
+func NewTransformer(t Transliterator) transform.Transformer {
+	return transformer{convert: t.Transliterate}
+}
+
+// NewReverseTransformer is like NewTransformer, but converts text using the reverse conversion
+func NewReverseTransformer(r Reverser) transform.Transformer {
+	return transformer{convert: r.Reverse}
+}
+
+type transformer struct {
+	convert func(s string) (Result, error)
+}
+
+// Reset implements the transform.Transformer interface. The transformer has no state.
+func (t transformer) Reset() {}
+
+// Transform implements the transform.Transformer interface
+func (t transformer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		n, ok := nextChunk(src[nSrc:], atEOF)
+		if !ok {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		out := src[nSrc : nSrc+n]
+		if r, _ := utf8.DecodeRune(out); !isLineBreak(r) {
+			res, err := t.convert(string(out))
+			if err != nil {
+				return nDst, nSrc, err
+			}
+			out = []byte(res.Output)
+		}
+		if len(dst)-nDst < len(out) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += copy(dst[nDst:], out)
+		nSrc += n
+	}
+	return nDst, nSrc, nil
+}
+
+func isLineBreak(r rune) bool {
+	return r == '\n' || r == '\r'
+}
+
+// nextChunk returns the length of the next chunk of input to convert, or false if more input is needed. A chunk is either a single line break, or text up to a line break or the start of a new word.
+func nextChunk(b []byte, atEOF bool) (int, bool) {
+	prevSpace := false
+	for i := 0; i < len(b); {
+		if !atEOF && !utf8.FullRune(b[i:]) {
+			return 0, false
+		}
+		r, size := utf8.DecodeRune(b[i:])
+		if isLineBreak(r) {
+			if i == 0 {
+				return size, true
+			}
+			return i, true
+		}
+		if prevSpace && !unicode.IsSpace(r) && !unicode.IsMark(r) {
+			return i, true
+		}
+		prevSpace = unicode.IsSpace(r)
+		i += size
+	}
+	return len(b), atEOF
+}
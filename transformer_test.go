@@ -0,0 +1,80 @@
+package translit
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"golang.org/x/text/transform"
+)
+
+// digraphs is a Reverser for testing, based on a converter with multi-character rules
+type digraphs struct{}
+
+var digraphConverter = NewConverter([]Rule{
+	{From: "ш", To: "sh"},
+	{From: "щ", To: "shch"},
+	{From: "шч", To: "XX"},
+	{From: "сх", To: "shch"},
+	{From: "а", To: "a"},
+	{From: "а", To: "A", Context: MustParseContext("#_#", nil)},
+}, func(r rune) bool { return r == ' ' })
+
+var digraphRevConverter = NewConverter([]Rule{
+	{From: "sh", To: "ш"},
+	{From: "shch", To: "щ"},
+	{From: "a", To: "а"},
+}, func(r rune) bool { return r == ' ' })
+
+func (digraphs) Scheme() Scheme { return Scheme{ID: "xx-digraphs"} }
+func (digraphs) Transliterate(s string) (Result, error) {
+	res, segs, err := digraphConverter.ConvertSegments(s, true)
+	return Result{Input: s, Output: res, Segments: segs}, err
+}
+func (digraphs) Reverse(s string) (Result, error) {
+	res, segs, err := digraphRevConverter.ConvertSegments(s, true)
+	return Result{Input: s, Output: res, Segments: segs}, err
+}
+
+func TestTransformer(t *testing.T) {
+	input := "шчаша щ\nа шащ\r\n\nа"
+	expect := "XXasha shch\nA shashch\r\n\nA"
+
+	got, _, err := transform.String(NewTransformer(digraphs{}), input)
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if got != expect {
+		t.Errorf(fsExpGot, expect, got)
+	}
+
+	// one byte at a time, splitting multi-byte characters and digraphs
+	r := transform.NewReader(iotest.OneByteReader(strings.NewReader(input)), NewTransformer(digraphs{}))
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if string(b) != expect {
+		t.Errorf(fsExpGot, expect, string(b))
+	}
+
+	var w strings.Builder
+	tw := transform.NewWriter(&w, NewReverseTransformer(digraphs{}))
+	for _, s := range []string{"sha s", "hch\nsh", "a"} {
+		if _, err := tw.Write([]byte(s)); err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if expect := "ша щ\nша"; w.String() != expect {
+		t.Errorf(fsExpGot, expect, w.String())
+	}
+
+	_, _, err = transform.String(NewTransformer(digraphs{}), "ша шx")
+	if err == nil {
+		t.Errorf("expected error for unmapped input")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -19,21 +20,12 @@ import (
 func ReadFile(fn string) ([]string, error) {
 	fn = filepath.Clean(fn)
 	var res []string
-	var scanner *bufio.Scanner
-	fh, err := os.Open(fn)
+	r, err := OpenFile(fn)
 	if err != nil {
-		return res, fmt.Errorf("failed to read '%s' : %v", fn, err)
-	}
-
-	if strings.HasSuffix(fn, ".gz") {
-		gz, err := gzip.NewReader(fh)
-		if err != nil {
-			return res, fmt.Errorf("failed to read '%s' : %v", fn, err)
-		}
-		scanner = bufio.NewScanner(gz)
-	} else {
-		scanner = bufio.NewScanner(fh)
+		return res, err
 	}
+	defer r.Close()
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		res = append(res, scanner.Text())
 	}
@@ -43,6 +35,34 @@ func ReadFile(fn string) ([]string, error) {
 	return res, nil
 }
 
+// OpenFile opens a file for reading, decompressing files ending with .gz
+func OpenFile(fn string) (io.ReadCloser, error) {
+	fn = filepath.Clean(fn)
+	fh, err := os.Open(fn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' : %v", fn, err)
+	}
+	if !strings.HasSuffix(fn, ".gz") {
+		return fh, nil
+	}
+	gz, err := gzip.NewReader(fh)
+	if err != nil {
+		fh.Close()
+		return nil, fmt.Errorf("failed to read '%s' : %v", fn, err)
+	}
+	return gzipFile{gz, fh}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	fh *os.File
+}
+
+func (f gzipFile) Close() error {
+	f.Reader.Close()
+	return f.fh.Close()
+}
+
 // func ReadFile(fName string) ([]string, error) {
 // 	b, err := ioutil.ReadFile(filepath.Clean(fName))
 // 	if err != nil {
@@ -78,6 +98,12 @@ func NFC(s string) string {
 	return normed
 }
 
+// NFD decomposes the string, with combining marks as separate characters
+func NFD(s string) string {
+	normed, _, _ := transform.String(norm.NFD, s)
+	return normed
+}
+
 func IsFile(fName string) bool {
 	if _, err := os.Stat(fName); os.IsNotExist(err) {
 		return false
@@ -0,0 +1,69 @@
+package translit
+
+// Scheme holds the metadata for a transliteration scheme
+type Scheme struct {
+	ID          string   // Scheme identifier, e.g. ru-Latn-x-roadsigns
+	Source      string   // Source language and script, e.g. ru-Cyrl
+	Target      string   // Target language and script, e.g. ru-Latn
+	Description string   // Human readable description
+	References  []string // Reference URLs
+}
+
+func (s Scheme) String() string {
+	return s.ID
+}
+
+// Result is the conversion result shared by all Transliterator implementations
+type Result struct {
+	Input    string    // Input string, after normalisation
+	Output   string    // Converted string
+	Segments []Segment // Alignment of input and output, in input order
+}
+
+// Segment aligns a part of the input string with the part of the output string it was converted into. Offsets are byte offsets into Result.Input and Result.Output (see Result.RuneSegments for rune offsets). Input characters that are deleted have an empty output range.
+type Segment struct {
+	InputStart  int
+	InputEnd    int
+	OutputStart int
+	OutputEnd   int
+	Rule        Rule // The mapping rule used, or a zero Rule if the input was copied as it is, or the segment was merged from several rules
+}
+
+// Transliterator is implemented by all transliteration schemes in this module
+type Transliterator interface {
+	// Scheme returns the scheme metadata
+	Scheme() Scheme
+	// Transliterate converts the input string from the source script into the target script
+	Transliterate(input string) (Result, error)
+}
+
+// Reverser is implemented by transliterators that also support conversion from the target script back into the source script
+type Reverser interface {
+	Transliterator
+	// Reverse converts the input string from the target script into the source script
+	Reverse(input string) (Result, error)
+}
+
+// IsReversible returns true if the transliterator implements the Reverser interface. Transliterators that are reversible in some configurations only may also implement a Reversible() bool method, which is then used.
+func IsReversible(t Transliterator) bool {
+	_, ok := t.(Reverser)
+	if r, conditional := t.(interface{ Reversible() bool }); ok && conditional {
+		return r.Reversible()
+	}
+	return ok
+}
+
+// IsDecomposed returns true if the transliterator decomposes (NFD) the input before conversion, so that its mapping table is written for decomposed input. Such transliterators implement a Decomposed() bool method, others compose (NFC) the input.
+func IsDecomposed(t Transliterator) bool {
+	d, ok := t.(interface{ Decomposed() bool })
+	return ok && d.Decomposed()
+}
+
+// Extensible is implemented by transliterators based on mapping tables, that can be modified or extended by user defined tables
+type Extensible interface {
+	Transliterator
+	// Table returns the mapping table in use
+	Table() Table
+	// Extend returns a copy of the transliterator, using its mapping table merged with the input table (see Table.Merge)
+	Extend(t Table) (Transliterator, error)
+}
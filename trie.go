@@ -0,0 +1,91 @@
+package translit
+
+// Trie is a prefix tree of mapping rules, used for longest-match lookup
+type Trie struct {
+	root *trieNode
+}
+
+type trieNode struct {
+	daus       map[rune]*trieNode
+	leaf       *Rule  // rule without context
+	contextual []Rule // rules with context, in the order added
+}
+
+func newTrieNode() *trieNode {
+	return &trieNode{daus: map[rune]*trieNode{}}
+}
+
+func (n *trieNode) isLeaf() bool {
+	return n.leaf != nil || len(n.contextual) > 0
+}
+
+// NewTrie creates an empty trie
+func NewTrie() *Trie {
+	return &Trie{root: newTrieNode()}
+}
+
+// Add adds a mapping from the source string to the target string (see AddRule)
+func (t *Trie) Add(from, to string) bool {
+	return t.AddRule(Rule{From: from, To: to})
+}
+
+// AddRule adds a mapping rule. If a rule with the same source string and context is already in the trie, the existing rule is kept, and false is returned (i.e., the first rule added wins).
+func (t *Trie) AddRule(rule Rule) bool {
+	if rule.From == "" {
+		return false
+	}
+	n := t.root
+	for _, r := range rule.From {
+		dau, ok := n.daus[r]
+		if !ok {
+			dau = newTrieNode()
+			n.daus[r] = dau
+		}
+		n = dau
+	}
+	if rule.Context.IsEmpty() {
+		if n.leaf != nil {
+			return false
+		}
+		n.leaf = &rule
+		return true
+	}
+	for _, r := range n.contextual {
+		if r.Context.String() == rule.Context.String() {
+			return false
+		}
+	}
+	n.contextual = append(n.contextual, rule)
+	return true
+}
+
+// Match returns the rule for the longest source string matching the input at position i, and the number of input runes matched. For each matching source string, rules with a context matching the input are tried first, in the order added, before the rule without context. If there is no match, the length is zero.
+func (t *Trie) Match(rs []rune, i int) (Rule, int) {
+	var buf [16]*trieNode
+	path := buf[:0]
+	n := t.root
+	for j := i; j < len(rs); j++ {
+		dau, ok := n.daus[rs[j]]
+		if !ok {
+			break
+		}
+		path = append(path, dau)
+		n = dau
+	}
+	for k := len(path) - 1; k >= 0; k-- {
+		n := path[k]
+		if !n.isLeaf() {
+			continue
+		}
+		end := i + k + 1
+		for _, r := range n.contextual {
+			if r.Context.Matches(rs, i, end) {
+				return r, k + 1
+			}
+		}
+		if n.leaf != nil {
+			return *n.leaf, k + 1
+		}
+	}
+	return Rule{}, 0
+}
@@ -0,0 +1,59 @@
+package translit
+
+import (
+	"testing"
+)
+
+func TestTrieMatch(t *testing.T) {
+	trie := NewTrie()
+	trie.Add("a", "1")
+	trie.Add("abc", "3")
+	trie.Add("x", "x1")
+	if trie.Add("x", "x2") {
+		t.Errorf("expected Add to return false for existing key")
+	}
+
+	tests := []struct {
+		input  string
+		expect string
+		n      int
+	}{
+		{"a", "1", 1},
+		{"ab", "1", 1}, // "ab" isn't a leaf
+		{"abc", "3", 3},
+		{"abcd", "3", 3},
+		{"x", "x1", 1},
+		{"b", "", 0},
+		{"", "", 0},
+	}
+	for _, test := range tests {
+		rule, n := trie.Match([]rune(test.input), 0)
+		if rule.To != test.expect || n != test.n {
+			t.Errorf("for '%s': expected '%s' (%d), got '%s' (%d)", test.input, test.expect, test.n, rule.To, n)
+		}
+	}
+}
+
+func TestConverterLongestMatch(t *testing.T) {
+	// shorter rule listed first should not shadow the longer one
+	c := NewConverter([]Rule{{From: "s", To: "s"}, {From: "h", To: "h"}, {From: "sh", To: "ʃ"}}, func(r rune) bool { return r == ' ' })
+
+	res, err := c.Convert("shs hs", true)
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if expect := "ʃs hs"; res != expect {
+		t.Errorf(fsExpGot, expect, res)
+	}
+
+	if _, err = c.Convert("shx", true); err == nil {
+		t.Errorf("expected error here!")
+	}
+	res, err = c.Convert("shx", false)
+	if err != nil {
+		t.Errorf("didn't expect error here! got %v", err)
+	}
+	if expect := "ʃx"; res != expect {
+		t.Errorf(fsExpGot, expect, res)
+	}
+}
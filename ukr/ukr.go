@@ -0,0 +1,43 @@
+package ukr
+
+import (
+	_ "embed"
+
+	tr "github.com/stts-se/translit"
+)
+
+// https://en.wikipedia.org/wiki/Romanization_of_Ukrainian
+// Official national system (2010)
+
+//go:embed tables/kmu2010.tsv
+var maptableData string
+
+var maptable = tr.MustParseTable("kmu2010.tsv", maptableData)
+
+var kmu2010Scheme = tr.Scheme{
+	ID:          "uk-Latn-x-kmu2010",
+	Source:      "uk-Cyrl",
+	Target:      "uk-Latn",
+	Description: "Ukrainian to Latin script, official national system (2010)",
+	References: []string{
+		"https://en.wikipedia.org/wiki/Romanization_of_Ukrainian",
+	},
+}
+
+var translit = tr.NewTableTranslit(tr.TableConfig{
+	Scheme:         kmu2010Scheme,
+	Table:          maptable,
+	UpcaseDigraphs: true,
+})
+
+// Translit converts Ukrainian to Latin script
+type Translit = tr.TableTranslit
+
+// NewTranslit returns a transliterator from Ukrainian to Latin script
+func NewTranslit() Translit {
+	return translit
+}
+
+func init() {
+	tr.Register(kmu2010Scheme, func() tr.Transliterator { return NewTranslit() })
+}
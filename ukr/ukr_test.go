@@ -0,0 +1,36 @@
+package ukr
+
+import (
+	"testing"
+)
+
+var fsExpGot = "Expected: %v got: %v"
+
+// Examples from the official 2010 resolution
+func TestTransliterate(t *testing.T) {
+	tlit := NewTranslit()
+	for input, expect := range map[string]string{
+		"Алушта Андрій Борщагівка":      "Alushta Andrii Borshchahivka",
+		"Вінниця Гадяч Згурський":       "Vinnytsia Hadiach Zghurskyi",
+		"Ґалаґан Ґорґани Донецьк":       "Galagan Gorgany Donetsk",
+		"Єнакієве Гаєвич Короп'є":       "Yenakiieve Haievych Koropie",
+		"Їжакевич Кадиївка Мар'їне":     "Yizhakevych Kadyivka Marine",
+		"Йосипівка Стрий Олексій":       "Yosypivka Stryi Oleksii",
+		"Київ Миколаїв Наталія":         "Kyiv Mykolaiv Nataliia",
+		"Ужгород Уляна Біла Церква":     "Uzhhorod Uliana Bila Tserkva",
+		"Юрій Корюківка Яготин":         "Yurii Koriukivka Yahotyn",
+		"Костянтин Знам’янка Тернопіль": "Kostiantyn Znamianka Ternopil",
+		// all caps
+		"ЖИТОМИР ЩАСТЯ ЗГУРСЬКИЙ": "ZHYTOMYR SHCHASTIA ZGHURSKYI",
+		"ЮРІЙ ЄНАКІЄВЕ Житомир":   "YURII YENAKIIEVE Zhytomyr",
+	} {
+		res, err := tlit.Transliterate(input)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+			continue
+		}
+		if res.Output != expect {
+			t.Errorf(fsExpGot, expect, res.Output)
+		}
+	}
+}
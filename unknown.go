@@ -0,0 +1,111 @@
+package translit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnknownAction is an action for input characters that aren't covered by the mapping rules, nor accepted as common characters
+type UnknownAction int
+
+const (
+	// UnknownDefault uses the default behaviour of the scheme (return an error for most schemes, see UnknownFail)
+	UnknownDefault UnknownAction = iota
+	// UnknownFail returns an error (*Error), and no output
+	UnknownFail
+	// UnknownReplace replaces the character with the replacement string
+	UnknownReplace
+	// UnknownPass copies the character to the output
+	UnknownPass
+	// UnknownDrop removes the character
+	UnknownDrop
+	// UnknownEscape replaces the character with a \uXXXX escape
+	UnknownEscape
+)
+
+var unknownActionNames = []string{"default", "fail", "replace", "pass", "drop", "escape"}
+
+func (a UnknownAction) String() string {
+	if a < 0 || int(a) >= len(unknownActionNames) {
+		return fmt.Sprintf("UnknownAction(%d)", int(a))
+	}
+	return unknownActionNames[a]
+}
+
+// UnknownPolicy defines how input characters that aren't covered by the mapping rules are handled. Except for UnknownDefault and UnknownFail, no error is returned for such characters.
+type UnknownPolicy struct {
+	Action      UnknownAction
+	Replacement string // Replacement string, for UnknownReplace
+}
+
+// unknownReplaceSeparator separates the replace action and the replacement string (e.g. replace:?)
+const unknownReplaceSeparator = ":"
+
+// ParseUnknownPolicy parses a policy name: default, fail, pass, drop, escape, or replace:<string> (replace without a string uses ?)
+func ParseUnknownPolicy(s string) (UnknownPolicy, error) {
+	name, replacement, hasReplacement := strings.Cut(s, unknownReplaceSeparator)
+	for i, n := range unknownActionNames {
+		if n != name {
+			continue
+		}
+		res := UnknownPolicy{Action: UnknownAction(i)}
+		if res.Action == UnknownReplace {
+			res.Replacement = "?"
+			if hasReplacement {
+				res.Replacement = replacement
+			}
+		} else if hasReplacement {
+			break
+		}
+		return res, nil
+	}
+	return UnknownPolicy{}, fmt.Errorf("invalid unknown character policy '%s' (expected %s, or %s%s<string>)", s, strings.Join(unknownActionNames, ", "), unknownActionNames[UnknownReplace], unknownReplaceSeparator)
+}
+
+func (p UnknownPolicy) String() string {
+	if p.Action == UnknownReplace {
+		return p.Action.String() + unknownReplaceSeparator + p.Replacement
+	}
+	return p.Action.String()
+}
+
+// Set implements the flag.Value interface (see ParseUnknownPolicy)
+func (p *UnknownPolicy) Set(s string) error {
+	res, err := ParseUnknownPolicy(s)
+	if err != nil {
+		return err
+	}
+	*p = res
+	return nil
+}
+
+// Handle returns the output for an unknown input character, or false if the character should be reported as an error (UnknownDefault and UnknownFail)
+func (p UnknownPolicy) Handle(r rune) (string, bool) {
+	switch p.Action {
+	case UnknownReplace:
+		return p.Replacement, true
+	case UnknownPass:
+		return string(r), true
+	case UnknownDrop:
+		return "", true
+	case UnknownEscape:
+		return codeFor(r), true
+	}
+	return "", false
+}
+
+// UnknownHandler is implemented by transliterators with a configurable policy for unknown input characters
+type UnknownHandler interface {
+	Transliterator
+	// WithUnknownPolicy returns a copy of the transliterator, using the policy
+	WithUnknownPolicy(p UnknownPolicy) Transliterator
+}
+
+// SetUnknownPolicy returns a copy of the transliterator using the policy. An error is returned if the transliterator doesn't implement UnknownHandler.
+func SetUnknownPolicy(t Transliterator, p UnknownPolicy) (Transliterator, error) {
+	h, ok := t.(UnknownHandler)
+	if !ok {
+		return t, fmt.Errorf("scheme %s doesn't support unknown character policies", t.Scheme())
+	}
+	return h.WithUnknownPolicy(p), nil
+}
@@ -0,0 +1,68 @@
+package translit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseUnknownPolicy(t *testing.T) {
+	tests := map[string]UnknownPolicy{
+		"default":   {},
+		"fail":      {Action: UnknownFail},
+		"replace":   {Action: UnknownReplace, Replacement: "?"},
+		"replace:_": {Action: UnknownReplace, Replacement: "_"},
+		"replace:":  {Action: UnknownReplace, Replacement: ""},
+		"pass":      {Action: UnknownPass},
+		"drop":      {Action: UnknownDrop},
+		"escape":    {Action: UnknownEscape},
+	}
+	for s, expect := range tests {
+		res, err := ParseUnknownPolicy(s)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		}
+		if res != expect {
+			t.Errorf(fsExpGot, expect, res)
+		}
+		if res2, _ := ParseUnknownPolicy(res.String()); res2 != res {
+			t.Errorf(fsExpGot, res, res2)
+		}
+	}
+	for _, s := range []string{"bogus", "fail:x", "Replace"} {
+		if _, err := ParseUnknownPolicy(s); err == nil {
+			t.Errorf("expected error for '%s'", s)
+		}
+	}
+}
+
+func TestConverterUnknownPolicy(t *testing.T) {
+	c := NewConverter([]Rule{{From: "а", To: "a"}, {From: "б", To: "b"}}, func(r rune) bool { return r == ' ' })
+	tests := []struct {
+		policy UnknownPolicy
+		expect string
+	}{
+		{UnknownPolicy{Action: UnknownReplace, Replacement: "?"}, "ab a?b?"},
+		{UnknownPolicy{Action: UnknownPass}, "ab axbé"},
+		{UnknownPolicy{Action: UnknownDrop}, "ab ab"},
+		{UnknownPolicy{Action: UnknownEscape}, `ab a\u0078b\u00E9`},
+	}
+	for _, test := range tests {
+		res, err := c.WithUnknownPolicy(test.policy).Convert("аб аxбé", true)
+		if err != nil {
+			t.Errorf("didn't expect error here! got %v", err)
+		}
+		if res != test.expect {
+			t.Errorf(fsExpGot, test.expect, res)
+		}
+	}
+	for _, p := range []UnknownPolicy{{}, {Action: UnknownFail}} {
+		res, err := c.WithUnknownPolicy(p).Convert("аб аxбé", true)
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Errorf(fsExpGot, "*Error", err)
+		}
+		if res != "" {
+			t.Errorf(fsExpGot, "", res)
+		}
+	}
+}